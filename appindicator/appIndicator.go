@@ -14,6 +14,8 @@ type Indicator struct {
 var (
 	icons                 []string
 	optionControlListener *gtk.MenuItem
+	optionAutoRotate      *gtk.MenuItem
+	autoRotateActive      bool                   // State of the auto-rotation
 	funcGetStringResource func(id string) string // Anonymous function that returns a string from the localizer
 )
 
@@ -23,6 +25,7 @@ const (
 	signalReboot          = "app-restart"
 	signalExit            = "app-exit"
 	signalControlListener = "app-listener-keyboard"
+	signalAutoRotate      = "app-auto-rotate"
 
 	// Index of icons inside slice "icons"
 	iconInactive = 0
@@ -76,6 +79,12 @@ func (indicator *Indicator) setupIndicator() {
 		_, _ = indicator.application.Emit(signalControlListener, glib.TYPE_NONE, newState, true)
 	})
 
+	optionAutoRotate, _ = gtk.MenuItemNewWithLabel(funcGetStringResource("start_auto_rotate"))
+	optionAutoRotate.Connect("activate", func(menuItem *gtk.MenuItem) {
+		// Emit signal to start/stop the auto-rotation
+		_, _ = indicator.application.Emit(signalAutoRotate, glib.TYPE_NONE, !autoRotateActive)
+	})
+
 	restartApp, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("restart"))
 	restartApp.Connect("activate", func(menuItem *gtk.MenuItem) {
 		// Emit signal to restart  application
@@ -91,6 +100,7 @@ func (indicator *Indicator) setupIndicator() {
 	// Add subitems to appindicator menu
 	menu.Add(openMainWindow)
 	menu.Add(optionControlListener)
+	menu.Add(optionAutoRotate)
 	menu.Add(restartApp)
 	menu.Add(exitApp)
 
@@ -112,3 +122,15 @@ func (indicator *Indicator) UpdateIconState(newState bool) {
 	}
 	optionControlListener.SetLabel(label)
 }
+
+// UpdateAutoRotateState Update the label of the option that controls the auto-rotation
+func (indicator *Indicator) UpdateAutoRotateState(active bool, paused bool) {
+	autoRotateActive = active
+	label := funcGetStringResource("start_auto_rotate")
+	if active && paused {
+		label = funcGetStringResource("stop_auto_rotate_paused")
+	} else if active {
+		label = funcGetStringResource("stop_auto_rotate")
+	}
+	optionAutoRotate.SetLabel(label)
+}
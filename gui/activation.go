@@ -0,0 +1,103 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// Section and options from config file related with the activation of windows
+const (
+	sectionActivation          = "activation"
+	optionActivationStrategies = "strategies" // Strategies tried in order until the window gets activated
+	optionActivationTimeout    = "timeout"    // Milliseconds every strategy has to activate the window
+
+	defaultActivationTimeout = 750
+)
+
+// Strategies used to activate windows and time each one of them has to do it
+var (
+	activationStrategies = []xlib.ActivationStrategy{xlib.ActivationEWMH, xlib.ActivationRaiseFocus}
+	activationTimeout    = time.Millisecond * defaultActivationTimeout
+)
+
+// Function that loads the strategies used to activate windows from config file
+func (mainGUI *MainGUI) loadActivationConfig() {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionActivation, optionActivationStrategies)
+	if strategiesString, ok := result.(string); ok && len(strategiesString) > 0 {
+		var strategies []xlib.ActivationStrategy
+		for _, strategy := range strings.Split(strategiesString, ",") {
+			switch strategy := xlib.ActivationStrategy(strategy); strategy {
+			case xlib.ActivationEWMH, xlib.ActivationRaiseFocus, xlib.ActivationUnmapMap:
+				strategies = append(strategies, strategy)
+			default:
+				fmt.Println("ERROR UNKNOWN ACTIVATION STRATEGY IN CONFIG FILE: ", strategy)
+			}
+		}
+		if len(strategies) > 0 {
+			activationStrategies = strategies
+		}
+	}
+
+	result, _ = mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionActivation, optionActivationTimeout)
+	if timeoutString, ok := result.(string); ok && len(timeoutString) > 0 {
+		if timeout, err := strconv.Atoi(timeoutString); err == nil && timeout > 0 {
+			activationTimeout = time.Millisecond * time.Duration(timeout)
+		}
+	}
+}
+
+/*
+Function that activates a window trying every activation strategy in order until one of them works. If the window has
+open transient windows (dialogs) the topmost one is activated after it, so it doesn't stay hidden behind the window.
+
+Returns:
+  - nil if the window was activated, the errors of all the strategies joined otherwise (errors.Join). If the window
+    doesn't exist anymore the error is returned right away (xlib.IsWindowGone).
+*/
+func activateWindow(window xlib.Window) error {
+	if err := activateWindowWithStrategies(window); err != nil {
+		return err
+	}
+	if transient := getTopmostTransient(window); transient != xlib.Window(0) {
+		fmt.Printf("(Callback) Activating transient window %d of window %d\n", transient, window)
+		if err := activateWindowWithStrategies(transient); err != nil && !xlib.IsWindowGone(err) {
+			fmt.Println("ERROR ACTIVATING TRANSIENT WINDOW: ", err)
+		}
+	}
+	return nil
+}
+
+// Function that activates a window trying every activation strategy in order until one of them works, some window
+// managers give the focus to the transient window (dialog) of the window instead and that counts as activated
+func activateWindowWithStrategies(window xlib.Window) error {
+	var errs []error
+	for _, strategy := range activationStrategies {
+		sent, err := xConn.ActivateWindowWith(window, strategy)
+		if xlib.IsWindowGone(err) {
+			return err
+		}
+		if sent {
+			err = xConn.WaitForWindowActivate(window, true, activationTimeout)
+			if err == nil {
+				return nil
+			}
+			if result, activeWindow := xConn.GetActiveWindow(); result &&
+				slices.Contains(getTransientChain(activeWindow), window) {
+				return nil
+			}
+		} else if err == nil {
+			err = fmt.Errorf("the requests couldn't be sent")
+		}
+		fmt.Printf("(Callback) Activation strategy \"%s\" failed: %s\n", strategy, err)
+		errs = append(errs, fmt.Errorf("%s: %w", strategy, err))
+	}
+	return errors.Join(errs...)
+}
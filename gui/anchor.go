@@ -0,0 +1,167 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// focusOutsideRotation What the next move does when the focus is on a window that is not in the current order
+type focusOutsideRotation string
+
+const (
+	focusOutsideResume  focusOutsideRotation = "resume"  // Continue from the last window of the current order
+	focusOutsideRestart focusOutsideRotation = "restart" // Start again from the beginning of the current order
+
+	// Option from config file (section rotation) with what to do when the focus is outside the rotation
+	optionFocusOutsideRotation = "focus_outside"
+)
+
+// What to do when the focus is outside the rotation, it's only accessed from the main loop
+var currentFocusOutsideRotation = focusOutsideResume
+
+// State of the anchor of the current order, the current index included. The window tracker moves it from the main
+// loop and the callbacks of the global hotkeys from the goroutine of the keyboard, so it's guarded by anchorMutex
+var (
+	restartRotation bool // Whether the next move starts from the beginning of the current order
+	anchorMutex     sync.Mutex
+)
+
+// Function that loads from config file what to do when the focus is outside the rotation
+func (mainGUI *MainGUI) loadFocusOutsideRotationConfig() {
+	result, _ := mainGUI.application.Emit(
+		signalGetConfig,
+		glib.TYPE_STRING,
+		sectionRotation,
+		optionFocusOutsideRotation,
+	)
+	switch value := focusOutsideRotation(result.(string)); value {
+	case focusOutsideResume, focusOutsideRestart:
+		currentFocusOutsideRotation = value
+	case "":
+	default:
+		fmt.Println("ERROR UNKNOWN FOCUS OUTSIDE ROTATION IN CONFIG FILE: ", value)
+	}
+}
+
+// Function that sets up the *gtk.ComboBoxText to choose what to do when the focus is outside the rotation
+func (contentTabVentanas *contentTabVentanas) setupComboBoxFocusOutsideRotation() {
+	mainGUI := &contentTabVentanas.mainGUI
+
+	obj, _ := mainGUI.builder.GetObject("comboBoxFocusOutsideRotation")
+	comboBoxFocusOutsideRotation := obj.(*gtk.ComboBoxText)
+	comboBoxFocusOutsideRotation.Append(string(focusOutsideResume), funcGetStringResource("gui_focus_outside_resume"))
+	comboBoxFocusOutsideRotation.Append(string(focusOutsideRestart), funcGetStringResource("gui_focus_outside_restart"))
+	comboBoxFocusOutsideRotation.SetActiveID(string(currentFocusOutsideRotation))
+	comboBoxFocusOutsideRotation.SetTooltipText(funcGetStringResource("gui_focus_outside_tooltip"))
+	comboBoxFocusOutsideRotation.Connect("changed", func(comboBox *gtk.ComboBoxText) {
+		currentFocusOutsideRotation = focusOutsideRotation(comboBox.GetActiveID())
+		_, _ = mainGUI.application.Emit(
+			signalUpdateConfig,
+			glib.TYPE_BOOLEAN,
+			sectionRotation,
+			optionFocusOutsideRotation,
+			string(currentFocusOutsideRotation),
+		)
+	})
+}
+
+/*
+Function that returns where the next move of the current order starts from.
+
+Returns:
+  - The current index of the current order
+  - Whether the focus is outside the rotation and the next move starts from the beginning of the current order
+*/
+func getRotationAnchor() (int, bool) {
+	anchorMutex.Lock()
+	defer anchorMutex.Unlock()
+	return currentIndex, restartRotation
+}
+
+// Function that sets the current index of the current order after a window of it was activated, the focus is back in
+// the rotation
+func setRotationAnchor(index int) {
+	anchorMutex.Lock()
+	defer anchorMutex.Unlock()
+	currentIndex = index
+	restartRotation = false
+}
+
+/*
+Function that anchors the current order to the window that got the focus, so the next move is relative to it even if
+it was focused outside the switcher (e.g. with the mouse). Callback of the event "_NET_ACTIVE_WINDOW" of the window
+tracker, it runs in the main loop.
+
+Parameters:
+  - windowId: Id of the focused window, "0" when no window has the focus
+*/
+func anchorCurrentIndex(windowId string) {
+	if len(currentOrder) == 0 {
+		return
+	}
+	index := slices.IndexFunc(currentOrder, func(w window) bool { return w.id == windowId })
+	anchorMutex.Lock()
+	defer anchorMutex.Unlock()
+	if index < 0 {
+		restartRotation = currentFocusOutsideRotation == focusOutsideRestart
+		return
+	}
+	restartRotation = false
+	// The window is already the current one, cloned windows keep their position
+	if currentIndex < len(currentOrder) && currentOrder[currentIndex].id == windowId {
+		return
+	}
+	fmt.Printf("(Callback) Focused window %s is in the current order, current index: %d\n", windowId, index)
+	currentIndex = index
+}
+
+/*
+Function that switches between the focused window of the rotation and the one of the rotation focused before it,
+following the focus history recorded by the window tracker. The windows out of the current order or out of the
+rotation scope are skipped, and the ones closed meanwhile are forgotten. Callback of global hotkey, it runs in the
+goroutine of the keyboard.
+*/
+func (mainGUI *MainGUI) flipWindow() {
+	fmt.Println("(Callback) flipWindow()")
+	activeWindowId := ""
+	if result, activeWindow := xConn.GetActiveWindow(); result && activeWindow != xlib.CURRENTWINDOW {
+		activeWindowId = getTransientRootId(strconv.FormatUint(uint64(activeWindow), 10))
+	}
+	focusHistoryMutex.Lock()
+	history := slices.Clone(focusHistory)
+	focusHistoryMutex.Unlock()
+
+	scope := newScopeFilter()
+	for _, windowId := range history {
+		index := slices.IndexFunc(currentOrder, func(w window) bool { return w.id == windowId })
+		if windowId == activeWindowId || index < 0 || !scope.contains(currentOrder[index]) {
+			continue
+		}
+		if !isWindowOpen(windowId) {
+			forgetFocus(windowId)
+			continue
+		}
+		fmt.Println("(Callback) Previous window:", currentOrder[index].windowToString())
+		err := activateWindow(getXWindow(windowId))
+		if xlib.IsWindowGone(err) {
+			forgetFocus(windowId)
+			continue
+		}
+		if err != nil {
+			fmt.Println("ERROR ACTIVATING WINDOW: ", err)
+			return
+		}
+		setRotationAnchor(index)
+		recordFocus(windowId)
+		showOverlay(currentOrder, index, true)
+		return
+	}
+	fmt.Println("(Callback) No window of the rotation was focused before the active one")
+}
@@ -0,0 +1,183 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"linux-windows-switcher/keyboard"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Section and options from config file related with the auto-rotation
+const (
+	sectionAutoRotate    = "autorotate"
+	optionAutoRotateIdle = "idle"  // Seconds without keyboard or mouse input before the rotation is resumed
+	optionDefaultDwell   = "dwell" // Seconds every window stays active when it has no dwell time of its own
+	prefixOptionDwell    = "dwell_"
+
+	defaultDwell      = 30
+	defaultIdleResume = 10
+	autoRotateTick    = 1000 // Milliseconds between two checks of the auto-rotation
+)
+
+// State of the auto-rotation, it's only accessed from the main loop
+var (
+	autoRotateActive    bool
+	autoRotatePaused    bool // Whether the rotation is waiting for the user to be idle
+	autoRotateMoving    bool // Whether the rotation is activating the next window
+	autoRotateTimer     glib.SourceHandle
+	autoRotateRemaining int // Seconds left before moving to the next window
+	autoRotateDwell     = defaultDwell
+	autoRotateIdle      = defaultIdleResume
+
+	// Dwell times set on the classes of the open windows. Structure: key: Option of the config file, value: Seconds
+	windowDwells = map[string]int{}
+)
+
+// Function that loads the config of the auto-rotation from config file and listens to the signal that controls it
+func (mainGUI *MainGUI) loadAutoRotateConfig() {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionAutoRotate, optionDefaultDwell)
+	if dwell, err := strconv.Atoi(result.(string)); err == nil && dwell > 0 {
+		autoRotateDwell = dwell
+	}
+
+	result, _ = mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionAutoRotate, optionAutoRotateIdle)
+	if idle, err := strconv.Atoi(result.(string)); err == nil && idle >= 0 {
+		autoRotateIdle = idle
+	}
+
+	// Handler of the signal that starts/stops the auto-rotation, emitted by the main window and the AppIndicator
+	mainGUI.application.Connect(signalAutoRotate, func(application *gtk.Application, active bool) {
+		mainGUI.setAutoRotate(active)
+	})
+}
+
+// Function that returns the option of the config file with the dwell time of a window, the windows of the same class
+// share it since the ids change between sessions and the titles while the windows are open
+func getOptionDwell(window window) string {
+	return prefixOptionDwell + hex.EncodeToString([]byte(strings.TrimPrefix(window.class, prefixClonedWindow)))
+}
+
+// Function that returns the seconds a window stays active during the auto-rotation
+func (mainGUI *MainGUI) getWindowDwell(window window) int {
+	optionDwell := getOptionDwell(window)
+	if dwell, exists := windowDwells[optionDwell]; exists {
+		return dwell
+	}
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionAutoRotate, optionDwell)
+	if dwell, err := strconv.Atoi(result.(string)); err == nil && dwell > 0 {
+		windowDwells[optionDwell] = dwell
+		return dwell
+	}
+	return autoRotateDwell
+}
+
+// Function that sets the seconds the windows of the class of a window stay active during the auto-rotation and saves
+// it in config file
+func (mainGUI *MainGUI) setWindowDwell(window window, dwell int) {
+	optionDwell := getOptionDwell(window)
+	windowDwells[optionDwell] = dwell
+	_, _ = mainGUI.application.Emit(
+		signalUpdateConfig,
+		glib.TYPE_BOOLEAN,
+		sectionAutoRotate,
+		optionDwell,
+		strconv.Itoa(dwell),
+	)
+	index, _ := getRotationAnchor()
+	if autoRotateActive && index < len(currentOrder) && getOptionDwell(currentOrder[index]) == optionDwell {
+		autoRotateRemaining = min(autoRotateRemaining, dwell)
+	}
+}
+
+// Function that returns the dwell time of the window the rotation is currently on
+func (mainGUI *MainGUI) getCurrentDwell() int {
+	index, _ := getRotationAnchor()
+	if index >= len(currentOrder) {
+		return autoRotateDwell
+	}
+	return mainGUI.getWindowDwell(currentOrder[index])
+}
+
+// Function that starts or stops the auto-rotation
+func (mainGUI *MainGUI) setAutoRotate(active bool) {
+	if active == autoRotateActive {
+		return
+	}
+	fmt.Printf("(Callback) setAutoRotate(active: %t)\n", active)
+	autoRotateActive = active
+	autoRotatePaused = false
+	if active {
+		autoRotateRemaining = mainGUI.getCurrentDwell()
+		autoRotateTimer = glib.TimeoutAdd(autoRotateTick, mainGUI.autoRotateStep)
+	} else if autoRotateTimer != 0 {
+		glib.SourceRemove(autoRotateTimer)
+		autoRotateTimer = 0
+	}
+	mainGUI.syncAutoRotateState()
+}
+
+/*
+Function that moves to the next window of the current order when the dwell time of the active one runs out, the same way
+the global hotkey "move forwards" does in the cycling mode "order". While the user is using the keyboard or mouse the
+rotation is paused, and the countdown starts again once there is no input for some seconds. The next window is
+activated in a goroutine, like the callbacks of the global hotkeys, since activating it may take a while.
+
+Returns:
+  - Whether the timer should keep running
+*/
+func (mainGUI *MainGUI) autoRotateStep() bool {
+	if !autoRotateActive {
+		return false
+	}
+	userActive := keyboard.IdleTime() < time.Duration(autoRotateIdle)*time.Second
+	if userActive != autoRotatePaused {
+		autoRotatePaused = userActive
+		mainGUI.syncAutoRotateState()
+	}
+	if autoRotatePaused {
+		autoRotateRemaining = mainGUI.getCurrentDwell()
+		return true
+	}
+
+	if autoRotateMoving {
+		return true
+	}
+	autoRotateRemaining--
+	if autoRotateRemaining > 0 || len(currentOrder) == 0 {
+		return true
+	}
+	autoRotateMoving = true
+	go func() {
+		mainGUI.moveNextWindow(false, 0)
+		glib.IdleAdd(func() {
+			autoRotateMoving = false
+			autoRotateRemaining = mainGUI.getCurrentDwell()
+		})
+	}()
+	return true
+}
+
+// Function that emits the signal to synchronize the state of the auto-rotation with the UI and AppIndicator
+func (mainGUI *MainGUI) syncAutoRotateState() {
+	_, _ = mainGUI.application.Emit(signalAutoRotateSyncState, glib.TYPE_NONE, autoRotateActive, autoRotatePaused)
+}
+
+// UpdateAutoRotateState Updates the button that controls the auto-rotation
+func (mainGUI *MainGUI) UpdateAutoRotateState(active bool, paused bool) {
+	if mainGUI == nil {
+		return
+	}
+	textLabel := funcGetStringResource("start_auto_rotate")
+	if active && paused {
+		textLabel = funcGetStringResource("stop_auto_rotate_paused")
+	} else if active {
+		textLabel = funcGetStringResource("stop_auto_rotate")
+	}
+	mainGUI.labelButtonAutoRotate.SetMarkup(textLabel)
+}
@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Time every window has to close before asking to force it
+const closeWindowTimeout = time.Second * 2
+
+/*
+Function that closes windows asking the window manager politely (_NET_CLOSE_WINDOW), the windows that are still open
+after closeWindowTimeout are forced to close (WM_DELETE_WINDOW and XKillClient) only after a confirmation.
+
+The windows are closed in a goroutine, the confirmation is shown in the main loop.
+*/
+func (mainGUI *MainGUI) closeWindows(windows []window) {
+	go func() {
+		for _, window := range windows {
+			if _, err := xConn.CloseWindow(getXWindow(window.id)); err != nil && !xlib.IsWindowGone(err) {
+				fmt.Println("ERROR CLOSING WINDOW: ", err)
+			}
+		}
+		var openWindows []window
+		deadline := time.Now().Add(closeWindowTimeout)
+		for _, window := range windows {
+			if err := xConn.WaitForWindowClose(getXWindow(window.id), time.Until(deadline)); err != nil {
+				openWindows = append(openWindows, window)
+			}
+		}
+		if len(openWindows) == 0 {
+			return
+		}
+		glib.IdleAdd(func() {
+			var titles []string
+			for _, window := range openWindows {
+				titles = append(titles, fmt.Sprintf("• %s (%s)", window.title, window.getClass()))
+			}
+			if mainGUI.showConfirmationDialog(
+				funcGetStringResource("gui_close_windows_force"),
+				strings.Join(titles, "\n"),
+				funcGetStringResource("gui_close_windows_force_button"),
+			) {
+				go forceCloseWindows(openWindows)
+			}
+		})
+	}()
+}
+
+// Function that forces windows to close, first with WM_DELETE_WINDOW and then killing their clients with XKillClient
+func forceCloseWindows(windows []window) {
+	for _, window := range windows {
+		xWindow := getXWindow(window.id)
+		sent, err := xConn.DeleteWindow(xWindow)
+		if xlib.IsWindowGone(err) {
+			continue
+		}
+		if sent && xConn.WaitForWindowClose(xWindow, closeWindowTimeout) == nil {
+			continue
+		}
+		if err != nil && !errors.Is(err, xlib.ErrDeleteNotSupported) {
+			fmt.Println("ERROR DELETING WINDOW: ", err)
+		}
+		if err = xConn.KillClient(xWindow); err != nil && !xlib.IsWindowGone(err) {
+			fmt.Println("ERROR KILLING CLIENT OF WINDOW: ", err)
+		}
+	}
+}
+
+// Function that closes every excluded window of the *gtk.TreeView of opened windows after a confirmation
+func (listaVentanas *listaVentanas) closeExcludedWindows() {
+	windows := listaVentanas.getExcludedWindows()
+	if len(windows) == 0 {
+		return
+	}
+	mainGUI := &listaVentanas.contentTabVentanas.mainGUI
+	var titles []string
+	for _, window := range windows {
+		titles = append(titles, fmt.Sprintf("• %s (%s)", window.title, window.getClass()))
+	}
+	msg := funcGetStringResource("gui_close_excluded_windows_confirmation")
+	if mainGUI.showConfirmationDialog(
+		strings.ReplaceAll(msg, "%d", strconv.Itoa(len(windows))),
+		strings.Join(titles, "\n"),
+		funcGetStringResource("gui_treeview_context_menu_close_window"),
+	) {
+		mainGUI.closeWindows(windows)
+	}
+}
+
+// Function that returns the excluded windows of the *gtk.TreeView of opened windows that are still open
+func (listaVentanas *listaVentanas) getExcludedWindows() []window {
+	var windows []window
+	var ids []string
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnExcluded)
+			goValue, _ := value.GoValue()
+			excluded := goValue.(bool)
+
+			value, _ = model.GetValue(iter, columnDeletedWindow)
+			goValue, _ = value.GoValue()
+			deleted := goValue.(bool)
+
+			if excluded && !deleted {
+				window := listaVentanas.getWindowFromRowIter(iter)
+				if !slices.Contains(ids, window.id) && isWindowOpen(window.id) {
+					ids = append(ids, window.id)
+					windows = append(windows, window)
+				}
+			}
+			return false
+		},
+	)
+	return windows
+}
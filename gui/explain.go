@@ -0,0 +1,243 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/gotk3/gotk3/pango"
+)
+
+// windowSkip Built-in reason why a window is never in the rotation, whatever the rules say
+type windowSkip string
+
+const (
+	skipNone      windowSkip = ""
+	skipOwnWindow windowSkip = "own_window"   // Window of the switcher itself
+	skipNoDesktop windowSkip = "no_desktop"   // Window on all the desktops or none (_NET_WM_DESKTOP -1), e.g. panels
+	skipType      windowSkip = "type"         // Window whose type is skipped, see skippedWindowTypes
+	skipTransient windowSkip = "transient"    // Transient window (dialog), it's activated along with its main window
+	skipTaskbar   windowSkip = "skip_taskbar" // Window with the state "_NET_WM_STATE_SKIP_TASKBAR"
+	skipPager     windowSkip = "skip_pager"   // Window with the state "_NET_WM_STATE_SKIP_PAGER"
+
+	explainPadding = 2 // Spaces between the columns of the output of the flag --explain
+)
+
+// windowVerdict Result of the filter of the windows of the rotation for a window
+type windowVerdict struct {
+	window   window
+	included bool
+	skip     windowSkip
+	rule     *windowRule // Rule that decided, nil if the window was skipped or no rule matched
+}
+
+// Function that returns the built-in reason why a window is never in the rotation, skipNone if it's not skipped
+func getWindowSkip(window window, applicationId string) windowSkip {
+	switch {
+	case strings.Contains(window.class, applicationId):
+		return skipOwnWindow
+	case window.desktop == -1:
+		return skipNoDesktop
+	case slices.Contains(skippedWindowTypes, window.windowType):
+		return skipType
+	case len(window.transientFor) > 0:
+		return skipTransient
+	case skipTaskbarWindows && window.skipTaskbar:
+		return skipTaskbar
+	case skipPagerWindows && window.skipPager:
+		return skipPager
+	}
+	return skipNone
+}
+
+/*
+Function that decides whether a window goes in the rotation: the windows of the switcher itself, the ones with desktop
+-1, the transient ones and the ones filtered by their type or state are skipped, the rest are decided by the rules.
+
+Parameters:
+  - rules: Rules to apply
+  - window: Window to check, as returned by listWindows
+  - applicationId: Id of the application, its windows are skipped
+*/
+func filterWindow(rules []*windowRule, window window, applicationId string) windowVerdict {
+	verdict := windowVerdict{window: window, skip: getWindowSkip(window, applicationId)}
+	if verdict.skip == skipNone {
+		verdict.included, verdict.rule = evaluateRules(rules, window)
+	}
+	return verdict
+}
+
+// Function that returns why the window is or isn't in the rotation
+func (verdict windowVerdict) reason() string {
+	switch {
+	case verdict.skip == skipType:
+		return fmt.Sprintf(funcGetStringResource("explain_skip_type"), getWindowTypeName(verdict.window.windowType))
+	case verdict.skip == skipTransient:
+		return fmt.Sprintf(funcGetStringResource("explain_skip_transient"), verdict.window.transientFor)
+	case verdict.skip != skipNone:
+		return funcGetStringResource("explain_skip_" + string(verdict.skip))
+	case verdict.rule != nil:
+		return fmt.Sprintf(
+			funcGetStringResource("explain_rule"),
+			funcGetStringResource("explain_rule_"+string(verdict.rule.action)),
+			funcGetStringResource("gui_rule_field_"+string(verdict.rule.field)),
+			funcGetStringResource("gui_rule_mode_"+string(verdict.rule.mode)),
+			verdict.rule.pattern,
+			verdict.rule.priority,
+		)
+	case verdict.included:
+		return funcGetStringResource("explain_no_rule_included")
+	default:
+		return funcGetStringResource("explain_no_rule_excluded")
+	}
+}
+
+// Function that returns whether the window is in the rotation, as text
+func (verdict windowVerdict) state() string {
+	if verdict.included {
+		return funcGetStringResource("explain_included")
+	}
+	return funcGetStringResource("explain_excluded")
+}
+
+// Function that runs the filter of the rotation over all the open windows
+func explainWindows(rules []*windowRule, applicationId string, includeIcons bool) []windowVerdict {
+	var verdicts []windowVerdict
+	for _, openWindow := range listWindows(includeIcons) {
+		verdicts = append(verdicts, filterWindow(rules, openWindow, applicationId))
+	}
+	return verdicts
+}
+
+/*
+ExplainWindows Prints why every open window is or isn't in the rotation, output of the CLI flag --explain. The GUI is
+not needed, the rules are read with getConfig and the config file is not modified.
+
+Parameters:
+  - xConn_: Connection to the X server
+  - applicationId: Id of the application, its windows are skipped
+  - getConfig: Function that returns the value of an option of the config file, empty if it doesn't exist
+  - funcGetStringResource_: Function that returns a string resource
+*/
+func ExplainWindows(
+	xConn_ *xlib.Conn,
+	applicationId string,
+	getConfig func(section string, option string) string,
+	funcGetStringResource_ func(id string) string,
+) {
+	xConn = xConn_
+	funcGetStringResource = funcGetStringResource_
+	loadWindowFilterConfig(getConfig)
+
+	rules, _, err := parseRules(getConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR READING RULES FROM CONFIG FILE: ", err)
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, explainPadding, ' ', 0)
+	_, _ = fmt.Fprintf(
+		writer,
+		"%s\tID\t%s\t%s\t%s\t%s\t%s\n",
+		strings.ToUpper(funcGetStringResource("explain_column_state")),
+		strings.ToUpper(funcGetStringResource("gui_treeview_column_desktop_name")),
+		strings.ToUpper(funcGetStringResource("gui_treeview_column_type")),
+		strings.ToUpper(funcGetStringResource("gui_treeview_column_class")),
+		strings.ToUpper(funcGetStringResource("gui_treeview_column_title")),
+		strings.ToUpper(funcGetStringResource("explain_column_reason")),
+	)
+	for _, verdict := range explainWindows(rules, applicationId, false) {
+		_, _ = fmt.Fprintf(
+			writer,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			verdict.state(),
+			verdict.window.id,
+			verdict.window.desktopName,
+			getWindowTypeName(verdict.window.windowType),
+			verdict.window.class,
+			verdict.window.title,
+			verdict.reason(),
+		)
+	}
+	_ = writer.Flush()
+}
+
+// Function that shows a *gtk.Dialog with all the open windows and why each of them is or isn't in the rotation
+func (contentTabVentanas *contentTabVentanas) showDialogExplainWindows() {
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(fmt.Sprintf("%s - %s", title, funcGetStringResource("explain_title")))
+	dialog.SetIcon(defaultAppIcon)
+	dialog.SetTransientFor(contentTabVentanas.mainGUI.window)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(700, 450)
+	_, _ = dialog.AddButton(funcGetStringResource("accept"), gtk.RESPONSE_ACCEPT)
+
+	contentArea, _ := dialog.GetContentArea()
+	contentArea.SetSpacing(10)
+	contentArea.SetMarginStart(10)
+	contentArea.SetMarginEnd(10)
+	contentArea.SetMarginTop(10)
+
+	listBox, _ := gtk.ListBoxNew()
+	listBox.SetSelectionMode(gtk.SELECTION_NONE)
+	applicationId := contentTabVentanas.mainGUI.application.GetApplicationID()
+	for _, verdict := range explainWindows(contentTabVentanas.rules, applicationId, true) {
+		listBox.Add(createRowExplainWindow(verdict))
+	}
+
+	scrolledWindow, _ := gtk.ScrolledWindowNew(nil, nil)
+	scrolledWindow.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scrolledWindow.SetShadowType(gtk.SHADOW_OUT)
+	scrolledWindow.SetVExpand(true)
+	scrolledWindow.Add(listBox)
+	contentArea.Add(scrolledWindow)
+
+	dialog.Connect("response", func(dialog *gtk.Dialog, response int) { dialog.Destroy() })
+	dialog.ShowAll()
+}
+
+// Function that creates the *gtk.ListBoxRow of a window of the view "explain": icon, state, title, class, type,
+// desktop name and reason
+func createRowExplainWindow(verdict windowVerdict) *gtk.ListBoxRow {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	box.SetMarginTop(4)
+	box.SetMarginBottom(4)
+	box.SetMarginStart(6)
+	box.SetMarginEnd(6)
+
+	var image *gtk.Image
+	if verdict.window.icon != nil {
+		image, _ = gtk.ImageNewFromPixbuf(verdict.window.icon)
+	} else {
+		image, _ = gtk.ImageNewFromIconName("image-missing", gtk.ICON_SIZE_LARGE_TOOLBAR)
+	}
+	box.PackStart(image, false, false, 0)
+
+	color := "red"
+	if verdict.included {
+		color = "green"
+	}
+	label, _ := gtk.LabelNew("")
+	label.SetMarkup(fmt.Sprintf(
+		"<span foreground=\"%s\"><b>%s</b></span>  %s\n<small>%s — %s — %s</small>\n<small><i>%s</i></small>",
+		color,
+		glib.MarkupEscapeText(verdict.state()),
+		glib.MarkupEscapeText(verdict.window.title),
+		glib.MarkupEscapeText(verdict.window.class),
+		glib.MarkupEscapeText(getWindowTypeName(verdict.window.windowType)),
+		glib.MarkupEscapeText(verdict.window.desktopName),
+		glib.MarkupEscapeText(verdict.reason()),
+	))
+	label.SetEllipsize(pango.ELLIPSIZE_END)
+	label.SetHAlign(gtk.ALIGN_START)
+	label.SetXAlign(0)
+	box.PackStart(label, true, true, 0)
+
+	row, _ := gtk.ListBoxRowNew()
+	row.Add(box)
+	return row
+}
@@ -0,0 +1,150 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// Suffix of the option of the config file (section hotkeys) that tells if a global hotkey is held
+const suffixOptionHold = "_hold"
+
+var (
+	// Global hotkeys that move between windows in hold mode. Structure: key: Name, value: Whether it's held
+	holdHotKeys      = map[string]bool{}
+	holdHotKeysMutex sync.RWMutex
+
+	/*
+		State of the hold cycling in progress: while the modifiers of a held hotkey are pressed every press only moves
+		the selection, the selected window is activated when the modifiers are released
+	*/
+	holdCycle struct {
+		active  bool
+		mode    cyclingMode
+		windows []window    // Windows walked, the current order or the MRU order when the cycling started
+		history []string    // Focus history when the cycling started
+		index   int         // Index of the selected window
+		scope   scopeFilter // Rotation scope when the cycling started
+	}
+	holdCycleMutex sync.Mutex
+)
+
+// Function that returns true if a global hotkey is held
+func isHoldHotKey(hotKeyName string) bool {
+	holdHotKeysMutex.RLock()
+	defer holdHotKeysMutex.RUnlock()
+	return holdHotKeys[hotKeyName]
+}
+
+// Function that sets whether a global hotkey is held
+func setHoldHotKey(hotKeyName string, hold bool) {
+	holdHotKeysMutex.Lock()
+	defer holdHotKeysMutex.Unlock()
+	holdHotKeys[hotKeyName] = hold
+}
+
+// Function that reads from the config file whether a global hotkey is held
+func (mainGUI *MainGUI) loadHoldHotKey(hotKeyName string) {
+	result, _ := mainGUI.application.Emit(
+		signalGetConfig,
+		glib.TYPE_STRING,
+		sectionHotKeys,
+		infoGlobalHotKeys[hotKeyName]+suffixOptionHold,
+	)
+	hold, _ := strconv.ParseBool(result.(string))
+	setHoldHotKey(hotKeyName, hold)
+}
+
+/*
+Function that moves the selection of the hold cycling, the first press starts the cycling from the active window.
+
+Parameters:
+  - hotKeyName: Name of the global hotkey pressed
+  - backwards: Whether to go backwards or forwards
+*/
+func (mainGUI *MainGUI) moveSelection(hotKeyName string, backwards bool) {
+	holdCycleMutex.Lock()
+	defer holdCycleMutex.Unlock()
+
+	activeWindowId := ""
+	if result, activeWindow := xConn.GetActiveWindow(); result && activeWindow != xlib.CURRENTWINDOW {
+		activeWindowId = getTransientRootId(strconv.FormatUint(uint64(activeWindow), 10))
+	}
+	if !holdCycle.active {
+		holdCycle.active = true
+		holdCycle.mode = getCyclingMode(hotKeyName)
+		holdCycle.scope = newScopeFilter()
+		if holdCycle.mode == cyclingModeMRU {
+			holdCycle.windows = getMRUOrder()
+			focusHistoryMutex.Lock()
+			holdCycle.history = slices.Clone(focusHistory)
+			focusHistoryMutex.Unlock()
+			holdCycle.index = slices.IndexFunc(holdCycle.windows, func(w window) bool { return w.id == activeWindowId })
+		} else {
+			holdCycle.windows = slices.Clone(currentOrder)
+			index, restart := getRotationAnchor()
+			holdCycle.index = index
+			if restart {
+				holdCycle.index = -1 // The focus is outside the rotation, it starts again from the beginning
+			}
+		}
+		if holdCycle.index < 0 && backwards {
+			holdCycle.index = 0 // The active window is not in the rotation, going backwards starts on the last window
+		}
+	}
+	length := len(holdCycle.windows)
+	for range length {
+		if backwards {
+			holdCycle.index = (holdCycle.index - 1 + length) % length
+		} else {
+			holdCycle.index = (holdCycle.index + 1) % length
+		}
+		selectedWindow := holdCycle.windows[holdCycle.index]
+		isActiveWindow := holdCycle.mode == cyclingModeMRU && selectedWindow.id == activeWindowId
+		if !isActiveWindow && holdCycle.scope.contains(selectedWindow) && isWindowOpen(selectedWindow.id) {
+			fmt.Println("(Callback) Selected window:", selectedWindow.windowToString())
+			showOverlay(holdCycle.windows, holdCycle.index, false)
+			return
+		}
+	}
+}
+
+// Function that activates the window selected by the hold cycling. Callback of the release of a held global hotkey
+func (mainGUI *MainGUI) commitSelection() {
+	holdCycleMutex.Lock()
+	defer holdCycleMutex.Unlock()
+	if !holdCycle.active {
+		return
+	}
+	holdCycle.active = false
+	hideOverlay()
+	if holdCycle.index < 0 || holdCycle.index >= len(holdCycle.windows) {
+		return
+	}
+	selectedWindow := holdCycle.windows[holdCycle.index]
+	fmt.Println("(Callback) Committing selected window:", selectedWindow.windowToString())
+	if err := activateWindow(getXWindow(selectedWindow.id)); err != nil {
+		fmt.Println("ERROR ACTIVATING WINDOW: ", err)
+		return
+	}
+	if holdCycle.mode == cyclingModeMRU {
+		focusHistoryMutex.Lock()
+		history := removeItem(slices.Clone(holdCycle.history), selectedWindow.id)
+		focusHistory = append([]string{selectedWindow.id}, history...)
+		focusHistoryMutex.Unlock()
+		return
+	}
+	// The selected window becomes the current window of the order, if the order didn't change meanwhile
+	if holdCycle.index < len(currentOrder) && currentOrder[holdCycle.index].id == selectedWindow.id {
+		setRotationAnchor(holdCycle.index)
+		return
+	}
+	if index := slices.IndexFunc(currentOrder, func(w window) bool { return w.id == selectedWindow.id }); index >= 0 {
+		setRotationAnchor(index)
+	}
+}
@@ -21,16 +21,26 @@ type MainGUI struct {
 	buttonControlListener      *gtk.Button
 	labelButtonControlListener *gtk.Label
 	imageButtonControlListener *gtk.Image
+	labelButtonAutoRotate      *gtk.Label
 }
 
 type window struct {
 	id          string
 	class       string
+	wmInstance  string // Instance (first string) of WM_CLASS, empty for the windows read from the *gtk.TreeView
+	wmClass     string // Class (second string) of WM_CLASS, empty for the windows read from the *gtk.TreeView
 	title       string
 	desktop     int
 	desktopName string
 	order       int
 	icon        *gdk.Pixbuf
+	geometry    string // Absolute geometry of the frame of the window: WIDTHxHEIGHT+X+Y
+	windowType  xlib.WindowType
+	skipTaskbar bool // Whether the window has the state "_NET_WM_STATE_SKIP_TASKBAR"
+	skipPager   bool // Whether the window has the state "_NET_WM_STATE_SKIP_PAGER"
+	// Id of the open window it's transient for (WM_TRANSIENT_FOR), e.g. the main window of a dialog, empty if none
+	transientFor string
+	transients   []string // Ids of the open windows that are transient for it, in the order of the client list
 }
 
 func (w window) windowToString() string {
@@ -75,6 +85,9 @@ const (
 	signalSetOrder        = "app-set-order"
 	signalDeleteRow       = "app-delete-window-order"
 
+	signalAutoRotate          = "app-auto-rotate"
+	signalAutoRotateSyncState = "app-auto-rotate-sync-state"
+
 	// Page where the configuration of global hotkeys is
 	pageContentGlobalHotkeys = 1
 )
@@ -85,10 +98,12 @@ func NewMainGUI(
 	showWindow_ bool,
 	funcGetResource_ func(resource string) []byte,
 	funcGetStringResource_ func(id string) string,
+	xConn_ *xlib.Conn,
 ) *MainGUI {
 	// Assign functions coming from main module
 	funcGetResource = funcGetResource_
 	funcGetStringResource = funcGetStringResource_
+	xConn = xConn_
 
 	// Fill global vars with their initial values
 	uiFile = string(funcGetResource(uiFileName))
@@ -100,10 +115,17 @@ func NewMainGUI(
 	// Creation of main GUI struct
 	mainGui := &MainGUI{application: application, builder: getNewBuilder()}
 	mainGui.initLocale()
+	mainGui.loadActivationConfig()
+	mainGui.loadOverlayConfig()
+	mainGui.loadRotationScopeConfig()
+	mainGui.loadFocusOutsideRotationConfig()
+	mainGui.loadAutoRotateConfig()
+	loadWindowFilterConfig(mainGui.getConfig)
 	mainGui.setupUi()
 	mainGui.setIconsUI()
 	mainGui.contentTabVentanas = mainGui.newContentTabVentanas()
 	mainGui.contentTabAtajos = mainGui.newContentTabAtajos()
+	mainGui.startWindowTracker()
 	return mainGui
 }
 
@@ -128,6 +150,14 @@ func (mainGUI *MainGUI) initLocale() {
 	obj, _ = mainGUI.builder.GetObject("labelButtonExit")
 	labelButtonExit := obj.(*gtk.Label)
 	labelButtonExit.SetMarkup(funcGetStringResource("exit"))
+
+	obj, _ = mainGUI.builder.GetObject("buttonAutoRotate")
+	buttonAutoRotate := obj.(*gtk.Button)
+	buttonAutoRotate.SetTooltipText(funcGetStringResource("gui_auto_rotate_tooltip"))
+
+	obj, _ = mainGUI.builder.GetObject("labelButtonAutoRotate")
+	labelButtonAutoRotate := obj.(*gtk.Label)
+	labelButtonAutoRotate.SetMarkup(funcGetStringResource("start_auto_rotate"))
 }
 
 // Config function
@@ -165,7 +195,7 @@ func (mainGUI *MainGUI) setupUi() {
 	eventBoxImageHeaderBar.Connect("button-release-event", func(box *gtk.EventBox, event *gdk.Event) bool {
 		eventButton := gdk.EventButtonNewFromEvent(event)
 		if eventButton.Button() == gdk.BUTTON_PRIMARY {
-			xlib.ClickWindow(xlib.CURRENTWINDOW, xlib.MBUTTON_RIGHT)
+			xConn.ClickWindow(xlib.CURRENTWINDOW, xlib.MBUTTON_RIGHT)
 		}
 		return false
 	})
@@ -208,6 +238,18 @@ func (mainGUI *MainGUI) setupUi() {
 	obj, _ = mainGUI.builder.GetObject("imageButtonControlListener")
 	mainGUI.imageButtonControlListener = obj.(*gtk.Image)
 
+	// Button to start/stop the auto-rotation
+	obj, _ = mainGUI.builder.GetObject("buttonAutoRotate")
+	buttonAutoRotate := obj.(*gtk.Button)
+	buttonAutoRotate.Connect("clicked", func(button *gtk.Button) {
+		// Emit signal to start/stop the auto-rotation
+		_, _ = mainGUI.application.Emit(signalAutoRotate, glib.TYPE_NONE, !autoRotateActive)
+	})
+
+	// Label of button that manages the auto-rotation
+	obj, _ = mainGUI.builder.GetObject("labelButtonAutoRotate")
+	mainGUI.labelButtonAutoRotate = obj.(*gtk.Label)
+
 	// Hide window button
 	obj, _ = mainGUI.builder.GetObject("buttonHideWindow")
 	buttonHideWindow := obj.(*gtk.Button)
@@ -251,6 +293,11 @@ func (mainGUI *MainGUI) setIconsUI() {
 	image = obj.(*gtk.Image)
 	image.SetFromPixbuf(getPixBufAtSize("restore.png", 24, 24))
 
+	// Image layout snapshots button
+	obj, _ = mainGUI.builder.GetObject("imageSnapshots")
+	image = obj.(*gtk.Image)
+	image.SetFromPixbuf(getPixBufAtSize("tabs-desktop.png", 24, 24))
+
 	// Image hide window button
 	obj, _ = mainGUI.builder.GetObject("imageHideWindow")
 	image = obj.(*gtk.Image)
@@ -288,6 +335,34 @@ func (mainGUI *MainGUI) showMessageDialog(messageType gtk.MessageType, msg strin
 	dialog.Destroy()
 }
 
+/*
+Function that asks for a confirmation using a *gtk.MessageDialog.
+
+Parameters:
+  - msg: Main message of dialog
+  - msg2: Secondary message
+  - acceptLabel: Label of the button that confirms
+
+Returns:
+  - Whether the user confirmed
+*/
+func (mainGUI *MainGUI) showConfirmationDialog(msg string, msg2 string, acceptLabel string) bool {
+	dialog := gtk.MessageDialogNew(mainGUI.window, gtk.DIALOG_MODAL, gtk.MESSAGE_QUESTION, gtk.BUTTONS_NONE, "%s", msg)
+	if len(msg2) > 0 {
+		dialog.FormatSecondaryText("%s", msg2)
+	}
+	_, _ = dialog.AddButton(funcGetStringResource("cancel"), gtk.RESPONSE_CANCEL)
+	_, _ = dialog.AddButton(acceptLabel, gtk.RESPONSE_ACCEPT)
+	dialog.SetDefaultResponse(gtk.RESPONSE_CANCEL)
+	dialog.SetPosition(gtk.WIN_POS_CENTER)
+	dialog.SetTransientFor(mainGUI.window)
+	dialog.SetTitle(title)
+	dialog.SetIcon(defaultAppIcon)
+	response := dialog.Run()
+	dialog.Destroy()
+	return response == gtk.RESPONSE_ACCEPT
+}
+
 // PresentWindow Present Main Window
 func (mainGUI *MainGUI) PresentWindow() {
 	mainGUI.window.Present()
@@ -0,0 +1,153 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Function that returns the id of the active window, empty if there's none. A transient window (dialog) counts as the
+// window it's transient for
+func getActiveWindowId() string {
+	result, activeWindow := xConn.GetActiveWindow()
+	if !result || activeWindow == xlib.CURRENTWINDOW || activeWindow == xlib.Window(0) {
+		return ""
+	}
+	return getTransientRootId(strconv.FormatUint(uint64(activeWindow), 10))
+}
+
+/*
+Function that returns the paths of the rows of a window in the *gtk.TreeView of active windows, the closed windows and
+the rows of transient windows (dialogs) are not taken into account.
+
+Returns:
+  - Paths of the rows of the window that are in the current order (not excluded), cloned rows included
+  - Paths of the rows of the window that are excluded
+*/
+func (listaVentanas *listaVentanas) getWindowRows(windowId string) ([]string, []string) {
+	var includedRows, excludedRows []string
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnId)
+			goValue, _ := value.GoValue()
+			id := goValue.(string)
+
+			value, _ = model.GetValue(iter, columnExcluded)
+			goValue, _ = value.GoValue()
+			excluded := goValue.(bool)
+
+			value, _ = model.GetValue(iter, columnDeletedWindow)
+			goValue, _ = value.GoValue()
+			deleted := goValue.(bool)
+
+			if id == windowId && !deleted && path.GetDepth() == 1 {
+				if excluded {
+					excludedRows = append(excludedRows, path.String())
+				} else {
+					includedRows = append(includedRows, path.String())
+				}
+			}
+			return false // loop through all rows in the treeview
+		},
+	)
+	return includedRows, excludedRows
+}
+
+// Function that toggles the column "Exclude" of a row, the same way the user does it from the *gtk.TreeView. The row
+// is moved and the order is set again by the handler of the signal "toggled"
+func (listaVentanas *listaVentanas) toggleExcludedRow(path string) {
+	obj, _ := listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("cellRenderExclude")
+	cellRendererToggleColumnExcluded := obj.(*gtk.CellRendererToggle)
+	_, _ = cellRendererToggleColumnExcluded.Emit("toggled", glib.TYPE_NONE, path)
+}
+
+/*
+Function that appends a window to the current order. If the window is excluded in the *gtk.TreeView it's included
+again, and if it's not in the *gtk.TreeView (e.g. a rule excludes it) a row is added for it. The row goes
+after the last window of the current order.
+*/
+func (listaVentanas *listaVentanas) addWindowToRotation(windowId string) {
+	includedRows, excludedRows := listaVentanas.getWindowRows(windowId)
+	if len(includedRows) > 0 {
+		fmt.Printf("(Callback) Window %s is already in the current order\n", windowId)
+		return
+	}
+	if len(excludedRows) > 0 {
+		listaVentanas.toggleExcludedRow(excludedRows[0])
+		return
+	}
+
+	var newWindow *window
+	for _, windowActive := range listWindows(false) {
+		if windowActive.id == windowId {
+			newWindow = &windowActive
+			break
+		}
+	}
+	if newWindow == nil || newWindow.desktop == -1 ||
+		strings.Contains(newWindow.class, listaVentanas.contentTabVentanas.mainGUI.application.GetApplicationID()) {
+		fmt.Printf("(Callback) Window %s can't be added to the current order\n", windowId)
+		return
+	}
+	newWindow.class = newWindow.getClass()
+	newWindow.icon = getWindowIcon(getXWindow(newWindow.id)) // Only the icon of the new window is fetched
+	newWindow.order = listaVentanas.treeStoreActiveWindows.IterNChildren(nil) + 1
+	listaVentanas.windowList = append(listaVentanas.windowList, *newWindow)
+	listaVentanas.addRow(*newWindow, false, nil)
+
+	// The new row is the last one, it's moved above the excluded windows
+	path := strconv.Itoa(newWindow.order - 1)
+	iterNewRow, _ := listaVentanas.treeStoreActiveWindows.GetIterFromString(path)
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnExcluded)
+			goValue, _ := value.GoValue()
+			if goValue.(bool) {
+				listaVentanas.treeStoreActiveWindows.MoveBefore(iterNewRow, iter)
+				return true
+			}
+			return false
+		},
+	)
+	// Emit signal to stablish order, a window was added
+	_, _ = listaVentanas.contentTabVentanas.mainGUI.application.Emit(signalSetOrder, glib.TYPE_NONE, true, true, true)
+}
+
+// Function that removes a window from the current order, its rows (cloned ones included) get excluded in the
+// *gtk.TreeView
+func (listaVentanas *listaVentanas) removeWindowFromRotation(windowId string) {
+	includedRows, _ := listaVentanas.getWindowRows(windowId)
+	if len(includedRows) == 0 {
+		fmt.Printf("(Callback) Window %s is not in the current order\n", windowId)
+		return
+	}
+	// The rows are moved once the toggles are handled, so the paths are still valid
+	for _, path := range includedRows {
+		listaVentanas.toggleExcludedRow(path)
+	}
+}
+
+// Function that appends the active window to the current order. Callback of global hotkey, the *gtk.TreeView is
+// updated in the main loop
+func (mainGUI *MainGUI) addActiveWindowToRotation() {
+	windowId := getActiveWindowId()
+	fmt.Printf("(Callback) addActiveWindowToRotation(window: %s)\n", windowId)
+	if len(windowId) > 0 {
+		glib.IdleAdd(func() { mainGUI.contentTabVentanas.windowList.addWindowToRotation(windowId) })
+	}
+}
+
+// Function that removes the active window from the current order. Callback of global hotkey, the *gtk.TreeView is
+// updated in the main loop
+func (mainGUI *MainGUI) removeActiveWindowFromRotation() {
+	windowId := getActiveWindowId()
+	fmt.Printf("(Callback) removeActiveWindowFromRotation(window: %s)\n", windowId)
+	if len(windowId) > 0 {
+		glib.IdleAdd(func() { mainGUI.contentTabVentanas.windowList.removeWindowFromRotation(windowId) })
+	}
+}
@@ -0,0 +1,196 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"linux-windows-switcher/libs/xlib"
+)
+
+// Time the window manager has to rearrange the windows after a monitor is connected before they are moved back to it
+const monitorSettleDelay = 1500 * time.Millisecond
+
+var (
+	// Monitors of the screen, they are kept up to date by the events of the window tracker
+	monitors      []xlib.Monitor
+	monitorsMutex sync.RWMutex
+
+	// Last known frame of every tracked window. Structure: key: Id, value: Frame
+	windowFrames      = map[string]xlib.Rectangle{}
+	windowFramesMutex sync.Mutex
+
+	// Layouts of the windows that were on a monitor when it was disconnected. Structure: key: Monitor, value: Layouts
+	undockedLayouts      = map[string][]windowLayout{}
+	undockedLayoutsMutex sync.Mutex
+)
+
+// Function that returns the monitors of the screen, they are read from the X server if the tracker didn't report them
+func getMonitors() []xlib.Monitor {
+	monitorsMutex.RLock()
+	if len(monitors) > 0 {
+		defer monitorsMutex.RUnlock()
+		return slices.Clone(monitors)
+	}
+	monitorsMutex.RUnlock()
+	return xConn.GetMonitors()
+}
+
+// Function that returns the monitor with a name, nil if it's not connected
+func getMonitorByName(name string) *xlib.Monitor {
+	for _, monitor := range getMonitors() {
+		if monitor.Name == name {
+			return &monitor
+		}
+	}
+	return nil
+}
+
+// Function that returns the primary monitor, or the first one if none is primary
+func getPrimaryMonitor() xlib.Monitor {
+	currentMonitors := getMonitors()
+	if index := slices.IndexFunc(currentMonitors, func(m xlib.Monitor) bool { return m.Primary }); index >= 0 {
+		return currentMonitors[index]
+	}
+	return currentMonitors[0]
+}
+
+// Function that saves the last known frame of a window
+func trackWindowFrame(windowId string, window xlib.Window) {
+	geometry, err := xConn.GetWindowGeometry(window)
+	if geometry == nil || err != nil {
+		return
+	}
+	windowFramesMutex.Lock()
+	defer windowFramesMutex.Unlock()
+	windowFrames[windowId] = geometry.Frame
+}
+
+// Function that forgets the frame of a closed window
+func forgetWindowFrame(windowId string) {
+	windowFramesMutex.Lock()
+	defer windowFramesMutex.Unlock()
+	delete(windowFrames, windowId)
+}
+
+/*
+Function that converts an absolute frame to a frame relative to the monitor it's on.
+
+Returns:
+  - The name of the monitor, empty if the monitors have no names (XRandR is not available)
+  - The frame relative to the monitor, or the absolute frame if the monitors have no names
+*/
+func toMonitorFrame(frame xlib.Rectangle) (string, xlib.Rectangle) {
+	currentMonitors := getMonitors()
+	monitor := currentMonitors[xlib.GetMonitorOf(currentMonitors, frame)]
+	if len(monitor.Name) == 0 {
+		return "", frame
+	}
+	frame.X -= monitor.Geometry.X
+	frame.Y -= monitor.Geometry.Y
+	return monitor.Name, frame
+}
+
+// Function that converts a frame relative to a monitor to an absolute frame, if the monitor is not connected the frame
+// is placed on the primary monitor. The frame is shrunk and moved to fit in the monitor.
+func fromMonitorFrame(monitorName string, frame xlib.Rectangle) xlib.Rectangle {
+	if len(monitorName) == 0 {
+		return frame
+	}
+	monitor := getMonitorByName(monitorName)
+	if monitor == nil {
+		primaryMonitor := getPrimaryMonitor()
+		monitor = &primaryMonitor
+	}
+	bounds := monitor.Geometry
+	frame.Width = min(frame.Width, bounds.Width)
+	frame.Height = min(frame.Height, bounds.Height)
+	frame.X = bounds.X + max(0, min(frame.X, bounds.Width-frame.Width))
+	frame.Y = bounds.Y + max(0, min(frame.Y, bounds.Height-frame.Height))
+	return frame
+}
+
+// Function that updates the monitors of the screen. Callback of the events of monitors of the window tracker
+func updateMonitors(event xlib.Event) {
+	monitorsMutex.Lock()
+	previousMonitors := slices.Clone(monitors)
+	switch event := event.(type) {
+	case xlib.MonitorAddedEvent:
+		fmt.Println("(Monitors) Monitor added:", event.Monitor.Name, event.Monitor.Geometry)
+		monitors = append(monitors, event.Monitor)
+	case xlib.MonitorRemovedEvent:
+		fmt.Println("(Monitors) Monitor removed:", event.Monitor.Name, event.Monitor.Geometry)
+		monitors = slices.DeleteFunc(monitors, func(m xlib.Monitor) bool { return m.Name == event.Monitor.Name })
+	case xlib.MonitorChangedEvent:
+		fmt.Println("(Monitors) Monitor changed:", event.Monitor.Name, event.Monitor.Geometry)
+		index := slices.IndexFunc(monitors, func(m xlib.Monitor) bool { return m.Name == event.Monitor.Name })
+		if index >= 0 {
+			monitors[index] = event.Monitor
+		}
+	}
+	monitorsMutex.Unlock()
+
+	switch event := event.(type) {
+	case xlib.MonitorRemovedEvent:
+		saveUndockedLayouts(previousMonitors, event.Monitor)
+	case xlib.MonitorAddedEvent:
+		undockedLayoutsMutex.Lock()
+		_, undocked := undockedLayouts[event.Monitor.Name]
+		undockedLayoutsMutex.Unlock()
+		if undocked {
+			time.AfterFunc(monitorSettleDelay, func() { restoreUndockedLayouts(event.Monitor.Name) })
+		}
+	}
+}
+
+// Function that saves the layouts of the windows that were on a monitor that got disconnected, the window manager
+// moves them to the remaining monitors afterwards
+func saveUndockedLayouts(previousMonitors []xlib.Monitor, removedMonitor xlib.Monitor) {
+	if len(removedMonitor.Name) == 0 {
+		return
+	}
+	var layouts []windowLayout
+	windowFramesMutex.Lock()
+	for windowId, frame := range windowFrames {
+		index := xlib.GetMonitorOf(previousMonitors, frame)
+		if index < 0 || previousMonitors[index].Name != removedMonitor.Name {
+			continue
+		}
+		frame.X -= removedMonitor.Geometry.X
+		frame.Y -= removedMonitor.Geometry.Y
+		layout := windowLayout{id: windowId, desktop: -1, geometry: frame.String(), monitor: removedMonitor.Name}
+		states, _ := xConn.GetWindowStates(getXWindow(windowId))
+		for state, active := range states {
+			if active {
+				layout.states = append(layout.states, string(state))
+			}
+		}
+		layouts = append(layouts, layout)
+	}
+	windowFramesMutex.Unlock()
+
+	undockedLayoutsMutex.Lock()
+	defer undockedLayoutsMutex.Unlock()
+	if len(layouts) > 0 {
+		undockedLayouts[removedMonitor.Name] = layouts
+	}
+}
+
+// Function that moves the windows that were on a monitor back to it when it gets connected again
+func restoreUndockedLayouts(monitorName string) {
+	undockedLayoutsMutex.Lock()
+	layouts := undockedLayouts[monitorName]
+	delete(undockedLayouts, monitorName)
+	undockedLayoutsMutex.Unlock()
+
+	if getMonitorByName(monitorName) == nil {
+		return // Disconnected again meanwhile
+	}
+	for _, layout := range layouts {
+		if isWindowOpen(layout.id) {
+			fmt.Printf("(Monitors) Moving window %s back to monitor %s\n", layout.id, monitorName)
+			restoreWindowLayout(getXWindow(layout.id), layout)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// cyclingMode Order followed by a global hotkey to move between windows
+type cyclingMode string
+
+const (
+	cyclingModeOrder cyclingMode = "order" // The current order of the windows set on the *gtk.TreeView
+	cyclingModeMRU   cyclingMode = "mru"   // Most recently used windows first, like Alt+Tab
+
+	// Suffix of the option of the config file (section hotkeys) with the cycling mode of a global hotkey
+	suffixOptionMode = "_mode"
+
+	// Time between presses of a global hotkey to keep walking the same MRU order instead of starting again
+	mruCycleTimeout = time.Second
+)
+
+var (
+	// Ids of the windows that had the focus, the most recent first. It's updated by the window tracker
+	focusHistory      []string
+	focusHistoryMutex sync.Mutex
+
+	// Cycling mode of every global hotkey that moves between windows. Structure: key: Name, value: Mode
+	cyclingModes      = map[string]cyclingMode{}
+	cyclingModesMutex sync.RWMutex
+
+	// State of the MRU walk in progress
+	mruCycle struct {
+		windows    []window  // MRU order when the walk started
+		history    []string  // Focus history when the walk started
+		index      int       // Index of the last window activated
+		lastWindow string    // Id of the last window activated
+		lastTime   time.Time // When the last window was activated
+	}
+	mruCycleMutex sync.Mutex
+)
+
+// Function that moves a window to the front of the focus history
+func recordFocus(windowId string) {
+	focusHistoryMutex.Lock()
+	defer focusHistoryMutex.Unlock()
+	focusHistory = append([]string{windowId}, removeItem(focusHistory, windowId)...)
+}
+
+// Function that removes a closed window from the focus history
+func forgetFocus(windowId string) {
+	focusHistoryMutex.Lock()
+	defer focusHistoryMutex.Unlock()
+	focusHistory = removeItem(focusHistory, windowId)
+}
+
+// Function that returns the cycling mode of a global hotkey
+func getCyclingMode(hotKeyName string) cyclingMode {
+	cyclingModesMutex.RLock()
+	defer cyclingModesMutex.RUnlock()
+	if mode, exists := cyclingModes[hotKeyName]; exists {
+		return mode
+	}
+	return cyclingModeOrder
+}
+
+// Function that returns true if a global hotkey moves between windows and so it has a cycling mode
+func hasCyclingMode(hotKeyName string) bool {
+	cyclingModesMutex.RLock()
+	defer cyclingModesMutex.RUnlock()
+	_, exists := cyclingModes[hotKeyName]
+	return exists
+}
+
+// Function that sets the cycling mode of a global hotkey
+func setCyclingMode(hotKeyName string, mode cyclingMode) {
+	cyclingModesMutex.Lock()
+	defer cyclingModesMutex.Unlock()
+	cyclingModes[hotKeyName] = mode
+}
+
+/*
+Function that returns the windows of the current order sorted by recency: first the windows in the focus history of
+the app and then the rest of them following "_NET_CLIENT_LIST_STACKING" from top to bottom. Cloned windows are only
+included once.
+*/
+func getMRUOrder() []window {
+	focusHistoryMutex.Lock()
+	history := slices.Clone(focusHistory)
+	focusHistoryMutex.Unlock()
+
+	var stacking []string
+	clientListStacking, err := xConn.GetWindowProperty(xConn.GetRootWindow(), "_NET_CLIENT_LIST_STACKING")
+	if err == nil && clientListStacking != nil {
+		for _, windowId := range clientListStacking.GetLong() {
+			stacking = append([]string{strconv.FormatInt(windowId, 10)}, stacking...)
+		}
+	}
+
+	// Anonymous function that returns the rank of a window, the lower the more recent
+	funcGetRank := func(windowId string) int {
+		if index := slices.Index(history, windowId); index >= 0 {
+			return index
+		}
+		if index := slices.Index(stacking, windowId); index >= 0 {
+			return len(history) + index
+		}
+		return len(history) + len(stacking)
+	}
+
+	var windows []window
+	var ids []string
+	for _, window := range currentOrder {
+		if !slices.Contains(ids, window.id) {
+			ids = append(ids, window.id)
+			windows = append(windows, window)
+		}
+	}
+	slices.SortStableFunc(windows, func(first window, second window) int {
+		return funcGetRank(first.id) - funcGetRank(second.id)
+	})
+	return windows
+}
+
+/*
+Function to move between windows following the most recently used order. Pressing the hotkey again before
+mruCycleTimeout keeps walking the same order, otherwise it starts again from the active window so a single press
+switches back to the previous window.
+*/
+func (mainGUI *MainGUI) moveRecentWindow(backwards bool) {
+	fmt.Printf("(Callback) moveRecentWindow(backwards: %t)\n", backwards)
+	mruCycleMutex.Lock()
+	defer mruCycleMutex.Unlock()
+
+	activeWindowId := ""
+	if result, activeWindow := xConn.GetActiveWindow(); result && activeWindow != xlib.CURRENTWINDOW {
+		activeWindowId = getTransientRootId(strconv.FormatUint(uint64(activeWindow), 10))
+	}
+	if time.Since(mruCycle.lastTime) > mruCycleTimeout || activeWindowId != mruCycle.lastWindow {
+		mruCycle.windows = getMRUOrder()
+		focusHistoryMutex.Lock()
+		mruCycle.history = slices.Clone(focusHistory)
+		focusHistoryMutex.Unlock()
+		mruCycle.index = slices.IndexFunc(mruCycle.windows, func(w window) bool { return w.id == activeWindowId })
+		if mruCycle.index < 0 && backwards {
+			mruCycle.index = 0 // The active window is not in the rotation, going backwards starts on the last window
+		}
+	}
+	length := len(mruCycle.windows)
+	if length == 0 || (length == 1 && mruCycle.windows[0].id == activeWindowId) {
+		return
+	}
+
+	scope := newScopeFilter()
+	nextIndex := mruCycle.index
+	for range length {
+		if backwards {
+			nextIndex = (nextIndex - 1 + length) % length
+		} else {
+			nextIndex = (nextIndex + 1) % length
+		}
+		nextWindow := mruCycle.windows[nextIndex]
+		if nextWindow.id == activeWindowId || !scope.contains(nextWindow) || !isWindowOpen(nextWindow.id) {
+			continue
+		}
+		fmt.Println("(Callback) Next recent window:", nextWindow.windowToString())
+		err := activateWindow(getXWindow(nextWindow.id))
+		if xlib.IsWindowGone(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Println("ERROR ACTIVATING WINDOW: ", err)
+			return
+		}
+		mruCycle.index = nextIndex
+		mruCycle.lastWindow = nextWindow.id
+		mruCycle.lastTime = time.Now()
+		showOverlay(mruCycle.windows, nextIndex, true)
+		// The windows passed through while walking don't count as used, like Alt+Tab
+		focusHistoryMutex.Lock()
+		focusHistory = append([]string{nextWindow.id}, removeItem(slices.Clone(mruCycle.history), nextWindow.id)...)
+		focusHistoryMutex.Unlock()
+		return
+	}
+}
+
+/*
+Function that moves between windows following the cycling mode of a global hotkey.
+
+Parameters:
+  - hotKeyName: Name of the global hotkey pressed
+  - backwards: Whether to go backwards or forwards
+*/
+func (mainGUI *MainGUI) moveWindowWithMode(hotKeyName string, backwards bool) {
+	if len(currentOrder) == 0 {
+		return
+	}
+	if isHoldHotKey(hotKeyName) {
+		mainGUI.moveSelection(hotKeyName, backwards)
+		return
+	}
+	if getCyclingMode(hotKeyName) == cyclingModeMRU {
+		mainGUI.moveRecentWindow(backwards)
+		return
+	}
+	mainGUI.moveNextWindow(backwards, 0)
+}
+
+// Function that reads the cycling mode of a global hotkey from the config file
+func (mainGUI *MainGUI) loadCyclingMode(hotKeyName string, defaultMode cyclingMode) {
+	mode := defaultMode
+	result, _ := mainGUI.application.Emit(
+		signalGetConfig,
+		glib.TYPE_STRING,
+		sectionHotKeys,
+		infoGlobalHotKeys[hotKeyName]+suffixOptionMode,
+	)
+	switch value := cyclingMode(result.(string)); value {
+	case cyclingModeOrder, cyclingModeMRU:
+		mode = value
+	case "":
+	default:
+		fmt.Println("ERROR UNKNOWN CYCLING MODE IN CONFIG FILE: ", value)
+	}
+	setCyclingMode(hotKeyName, mode)
+}
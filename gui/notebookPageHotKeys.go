@@ -1,12 +1,14 @@
 package gui
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"linux-windows-switcher/keyboard"
+	"linux-windows-switcher/libs/xlib"
 
 	"github.com/gotk3/gotk3/gdk"
 	"github.com/gotk3/gotk3/glib"
@@ -35,6 +37,9 @@ const (
 
 	// Key limit to every single global hotkey
 	keyLimit = 3
+
+	// Number of global hotkeys that go directly to a position of the current order
+	slotHotKeys = 9
 )
 
 var (
@@ -74,6 +79,13 @@ func (contentTabAtajos *contentTabAtajos) setupContentTabAtajos() {
 	infoGlobalHotKeys[moveForwards] = "move_forwards"
 	infoGlobalHotKeys[moveBackwards] = "move_backwards"
 
+	// Cycling mode of the global hotkeys that move between windows
+	contentTabAtajos.mainGUI.loadCyclingMode(moveForwards, cyclingModeOrder)
+	contentTabAtajos.mainGUI.loadCyclingMode(moveBackwards, cyclingModeOrder)
+	for _, hotKeyName := range []string{moveForwards, moveBackwards} {
+		contentTabAtajos.mainGUI.loadHoldHotKey(hotKeyName)
+	}
+
 	// Create signal to update the ListBoxRow containing the global hotkeys whenever a hotkey is set/modified
 	_, _ = glib.SignalNew("listbox-update-hotkey")
 
@@ -94,6 +106,52 @@ func (contentTabAtajos *contentTabAtajos) setupContentTabAtajos() {
 	funcAddHotKey(moveForwards, contentTabAtajos.mainGUI.moveForwards)
 	funcAddHotKey(moveBackwards, contentTabAtajos.mainGUI.moveBackwards)
 
+	// Global hotkeys that go directly to a position of the current order
+	for slot := 1; slot <= slotHotKeys; slot++ {
+		name := fmt.Sprintf(funcGetStringResource("hotkey_go_to_slot"), slot)
+		infoGlobalHotKeys[name] = fmt.Sprintf("go_to_slot_%d", slot)
+		funcAddHotKey(name, func() { contentTabAtajos.mainGUI.goToSlot(slot - 1) })
+	}
+	addActiveWindow := funcGetStringResource("hotkey_add_to_rotation")
+	infoGlobalHotKeys[addActiveWindow] = "add_to_rotation"
+	funcAddHotKey(addActiveWindow, contentTabAtajos.mainGUI.addActiveWindowToRotation)
+	removeActiveWindow := funcGetStringResource("hotkey_remove_from_rotation")
+	infoGlobalHotKeys[removeActiveWindow] = "remove_from_rotation"
+	funcAddHotKey(removeActiveWindow, contentTabAtajos.mainGUI.removeActiveWindowFromRotation)
+	flipWindow := funcGetStringResource("hotkey_flip")
+	infoGlobalHotKeys[flipWindow] = "flip"
+	funcAddHotKey(flipWindow, contentTabAtajos.mainGUI.flipWindow)
+	goToFirstSlot := funcGetStringResource("hotkey_go_to_first_slot")
+	infoGlobalHotKeys[goToFirstSlot] = "go_to_first_slot"
+	funcAddHotKey(goToFirstSlot, func() { contentTabAtajos.mainGUI.goToSlot(0) })
+	goToLastSlot := funcGetStringResource("hotkey_go_to_last_slot")
+	infoGlobalHotKeys[goToLastSlot] = "go_to_last_slot"
+	funcAddHotKey(goToLastSlot, func() { contentTabAtajos.mainGUI.goToSlot(-1) })
+
+	// Global hotkeys that change the state of the active window
+	for _, state := range xlib.WindowStates {
+		name := funcGetStringResource("hotkey_toggle_" + string(state))
+		infoGlobalHotKeys[name] = "toggle_" + string(state)
+		funcAddHotKey(name, func() { contentTabAtajos.mainGUI.toggleActiveWindowState(state) })
+	}
+	minimizeActiveWindow := funcGetStringResource("hotkey_minimize")
+	infoGlobalHotKeys[minimizeActiveWindow] = "minimize"
+	funcAddHotKey(minimizeActiveWindow, contentTabAtajos.mainGUI.minimizeActiveWindow)
+	restoreLastSnapshot := funcGetStringResource("hotkey_restore_snapshot")
+	infoGlobalHotKeys[restoreLastSnapshot] = "restore_snapshot"
+	funcAddHotKey(restoreLastSnapshot, contentTabAtajos.mainGUI.restoreLastSnapshot)
+	searchWindows := funcGetStringResource("hotkey_search_windows")
+	infoGlobalHotKeys[searchWindows] = "search_windows"
+	funcAddHotKey(searchWindows, contentTabAtajos.mainGUI.showWindowPicker)
+
+	// The global hotkeys that move between windows can be held, the selected window is activated on release
+	for _, hotKey := range contentTabAtajos.listHotKeys {
+		if hasCyclingMode(hotKey.Name) {
+			hotKey.Hold = isHoldHotKey(hotKey.Name)
+			hotKey.OnRelease = contentTabAtajos.mainGUI.commitSelection
+		}
+	}
+
 	obj, _ = contentTabAtajos.mainGUI.builder.GetObject("listBoxGlobalHotKeys")
 	contentTabAtajos.listBoxHotKeys = obj.(*gtk.ListBox)
 
@@ -104,10 +162,19 @@ func (contentTabAtajos *contentTabAtajos) setupContentTabAtajos() {
 	}
 	// ----------------------------------------------------------------------------------------------------
 
+	// Create signal to update the ListBoxRow containing the global hotkeys whenever the hotkeys are registered
+	_, _ = glib.SignalNew("listbox-update-hotkey-error")
+
 	// Handler of signal "app-listener-set-hotkeys"
 	contentTabAtajos.mainGUI.application.Connect(
 		signalSetHotKeys,
-		func(application *gtk.Application) { keyboard.SetHotKeys(contentTabAtajos.listHotKeys) },
+		func(application *gtk.Application) {
+			keyboard.SetHotKeys(contentTabAtajos.listHotKeys)
+			// Every row shows the error, if any, returned when registering its hotkey
+			contentTabAtajos.listBoxHotKeys.GetChildren().Foreach(func(item any) {
+				_, _ = item.(*gtk.Widget).Emit("listbox-update-hotkey-error", glib.TYPE_NONE)
+			})
+		},
 	)
 
 	// Emit signal to activate the global hotkey listener when the app starts
@@ -184,11 +251,40 @@ func (contentTabAtajos *contentTabAtajos) createListBoxRowHotKey(hotKey *keyboar
 
 	obj, _ = builder.GetObject("labelKeysHotKey")
 	labelKeysHotKey := obj.(*gtk.Label)
-	if len(hotKey.HotKeys) == 0 {
-		labelKeysHotKey.SetMarkup(emtpyHotKey)
-	} else {
-		labelKeysHotKey.SetMarkup(strings.Join(hotKey.HotKeys, " + "))
+
+	// Anonymous function that shows the keys of the hotkey and the error returned when registering it, if any
+	funcSetLabelKeysHotKey := func() {
+		if len(hotKey.HotKeys) == 0 {
+			labelKeysHotKey.SetMarkup(emtpyHotKey)
+			labelKeysHotKey.SetTooltipText("")
+			return
+		}
+		keys := strings.Join(hotKey.HotKeys, " + ")
+		if hotKey.GrabError == nil {
+			labelKeysHotKey.SetMarkup(keys)
+			labelKeysHotKey.SetTooltipText("")
+			return
+		}
+		msgError := funcGetStringResource("hotkey_error_grab")
+		if errors.Is(hotKey.GrabError, xlib.ErrKeyAlreadyGrabbed) {
+			msgError = funcGetStringResource("hotkey_error_already_grabbed")
+		} else if errors.Is(hotKey.GrabError, xlib.ErrInvalidKeyCombination) {
+			msgError = funcGetStringResource("hotkey_error_invalid_combination")
+		}
+		labelKeysHotKey.SetMarkup(
+			fmt.Sprintf(
+				"%s\n<span color='tomato' size='small'><b>%s:</b> %s</span>",
+				keys,
+				funcGetStringResource("error"),
+				msgError,
+			),
+		)
+		labelKeysHotKey.SetTooltipText(msgError)
 	}
+	funcSetLabelKeysHotKey()
+
+	// Handler of signal "listbox-update-hotkey-error" used to show the error of a hotkey
+	listBoxRowGlobalHotKey.Connect("listbox-update-hotkey-error", func(row *gtk.ListBoxRow) { funcSetLabelKeysHotKey() })
 
 	// Anonymous function that updates the state of a global hotkey whenever is enabled/disabled
 	functionUpdateHotKey := func(hotkey keyboard.HotKey) bool {
@@ -234,7 +330,8 @@ func (contentTabAtajos *contentTabAtajos) createListBoxRowHotKey(hotKey *keyboar
 		"listbox-update-hotkey", func(button *gtk.Button) {
 			result := functionUpdateHotKey(*hotKey)
 			if result {
-				labelKeysHotKey.SetMarkup(strings.Join(hotKey.HotKeys, " + "))
+				hotKey.GrabError = nil
+				funcSetLabelKeysHotKey()
 				buttonDisableHotKey.SetSensitive(true)
 			}
 		},
@@ -244,6 +341,50 @@ func (contentTabAtajos *contentTabAtajos) createListBoxRowHotKey(hotKey *keyboar
 		buttonDisableHotKey.SetActive(true)
 	}
 
+	// Cycling mode of the global hotkeys that move between windows
+	if hasCyclingMode(hotKey.Name) {
+		obj, _ = builder.GetObject("comboBoxModeHotKey")
+		comboBoxModeHotKey := obj.(*gtk.ComboBoxText)
+		comboBoxModeHotKey.Append(string(cyclingModeOrder), funcGetStringResource("hotkey_mode_order"))
+		comboBoxModeHotKey.Append(string(cyclingModeMRU), funcGetStringResource("hotkey_mode_mru"))
+		comboBoxModeHotKey.SetActiveID(string(getCyclingMode(hotKey.Name)))
+		comboBoxModeHotKey.SetTooltipText(funcGetStringResource("hotkey_mode_tooltip"))
+		comboBoxModeHotKey.Connect("changed", func(comboBox *gtk.ComboBoxText) {
+			mode := cyclingMode(comboBox.GetActiveID())
+			setCyclingMode(hotKey.Name, mode)
+			_, _ = contentTabAtajos.mainGUI.application.Emit(
+				signalUpdateConfig,
+				glib.TYPE_BOOLEAN,
+				sectionHotKeys,
+				infoGlobalHotKeys[hotKey.Name]+suffixOptionMode,
+				string(mode),
+			)
+		})
+		comboBoxModeHotKey.Show()
+
+		obj, _ = builder.GetObject("checkButtonHoldHotKey")
+		checkButtonHoldHotKey := obj.(*gtk.CheckButton)
+		checkButtonHoldHotKey.SetLabel(funcGetStringResource("hotkey_hold"))
+		checkButtonHoldHotKey.SetTooltipText(funcGetStringResource("hotkey_hold_tooltip"))
+		checkButtonHoldHotKey.SetActive(hotKey.Hold)
+		checkButtonHoldHotKey.Connect("toggled", func(button *gtk.CheckButton) {
+			hotKey.Hold = button.GetActive()
+			setHoldHotKey(hotKey.Name, hotKey.Hold)
+			result, _ := contentTabAtajos.mainGUI.application.Emit(
+				signalUpdateConfig,
+				glib.TYPE_BOOLEAN,
+				sectionHotKeys,
+				infoGlobalHotKeys[hotKey.Name]+suffixOptionHold,
+				strconv.FormatBool(hotKey.Hold),
+			)
+			// The hotkeys are registered again so the key grabber knows which ones are held
+			if result.(bool) && !hotKey.Disabled {
+				_, _ = contentTabAtajos.mainGUI.application.Emit(signalControlListener, glib.TYPE_NONE, true, false)
+			}
+		})
+		checkButtonHoldHotKey.Show()
+	}
+
 	buttonChangeHotKey.SetSensitive(!buttonDisableHotKey.GetActive())
 	buttonDisableHotKey.SetSensitive(len(hotKey.HotKeysKeyCodes) > 0)
 	return listBoxRowGlobalHotKey
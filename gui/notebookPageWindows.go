@@ -2,6 +2,7 @@ package gui
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -15,17 +16,17 @@ import (
 type contentTabVentanas struct {
 	mainGUI                    MainGUI
 	listWindowClass            []window
-	listPreferredClasses       []string
-	listExcludedClasses        []string
+	rules                      []*windowRule
 	expander                   *gtk.Expander
 	listBoxActiveWindowClasses *gtk.ListBox
-	listBoxPreferredClasses    *gtk.ListBox
-	listBoxExcludedClasses     *gtk.ListBox
+	listBoxIncludeRules        *gtk.ListBox
+	listBoxExcludeRules        *gtk.ListBox
 	windowList                 *listaVentanas
 }
 
 const (
-	// Section from config file related with the preferred/excluded classes config
+	// Section from config file related with the preferred/excluded classes config, they are converted to rules when the
+	// config is loaded
 	sectionClasses = "classes"
 
 	// Options inside config file
@@ -54,18 +55,30 @@ func (contentTabVentanas *contentTabVentanas) initLocale() {
 	labelTitleListBoxActiveWindowClasses := obj.(*gtk.Label)
 	labelTitleListBoxActiveWindowClasses.SetMarkup(funcGetStringResource("gui_label_title_listbox_active_classes"))
 
-	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelTitleListBoxPreferredClasses")
-	labelTitleListBoxPreferredClasses := obj.(*gtk.Label)
-	labelTitleListBoxPreferredClasses.SetMarkup(funcGetStringResource("gui_label_title_listbox_preferred_classes"))
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelTitleListBoxIncludeRules")
+	labelTitleListBoxIncludeRules := obj.(*gtk.Label)
+	labelTitleListBoxIncludeRules.SetMarkup(funcGetStringResource("gui_label_title_listbox_include_rules"))
 
-	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelTitleListBoxExcludedClasses")
-	labelTitleListBoxExcludedClasses := obj.(*gtk.Label)
-	labelTitleListBoxExcludedClasses.SetMarkup(funcGetStringResource("gui_label_title_listbox_excluded_classes"))
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelTitleListBoxExcludeRules")
+	labelTitleListBoxExcludeRules := obj.(*gtk.Label)
+	labelTitleListBoxExcludeRules.SetMarkup(funcGetStringResource("gui_label_title_listbox_exclude_rules"))
+
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelButtonAddIncludeRule")
+	labelButtonAddIncludeRule := obj.(*gtk.Label)
+	labelButtonAddIncludeRule.SetMarkup(funcGetStringResource("gui_add_rule"))
+
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelButtonAddExcludeRule")
+	labelButtonAddExcludeRule := obj.(*gtk.Label)
+	labelButtonAddExcludeRule.SetMarkup(funcGetStringResource("gui_add_rule"))
 
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelButtonRefreshWindowClasses")
 	labelButtonRefreshWindowClasses := obj.(*gtk.Label)
 	labelButtonRefreshWindowClasses.SetMarkup(funcGetStringResource("refresh"))
 
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelButtonExplainWindows")
+	labelButtonExplainWindows := obj.(*gtk.Label)
+	labelButtonExplainWindows.SetMarkup(funcGetStringResource("explain_button"))
+
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelTitleWindowsOrder")
 	labelTitleWindowsOrder := obj.(*gtk.Label)
 	labelTitleWindowsOrder.SetMarkup(funcGetStringResource("gui_title_windows_order"))
@@ -89,6 +102,18 @@ func (contentTabVentanas *contentTabVentanas) initLocale() {
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelRestoreDefaultOrder")
 	labelRestoreDefaultOrder := obj.(*gtk.Label)
 	labelRestoreDefaultOrder.SetMarkup(funcGetStringResource("gui_label_restore_default_order"))
+
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelSnapshots")
+	labelSnapshots := obj.(*gtk.Label)
+	labelSnapshots.SetMarkup(funcGetStringResource("gui_label_snapshots"))
+
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelRotationScopeTitle")
+	labelRotationScopeTitle := obj.(*gtk.Label)
+	labelRotationScopeTitle.SetMarkup(fmt.Sprintf("%s:", funcGetStringResource("gui_label_rotation_scope")))
+
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelFocusOutsideRotationTitle")
+	labelFocusOutsideRotationTitle := obj.(*gtk.Label)
+	labelFocusOutsideRotationTitle.SetMarkup(fmt.Sprintf("%s:", funcGetStringResource("gui_label_focus_outside")))
 }
 
 // Config function
@@ -96,11 +121,11 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 	obj, _ := contentTabVentanas.mainGUI.builder.GetObject("listBoxActiveWindowClasses")
 	contentTabVentanas.listBoxActiveWindowClasses = obj.(*gtk.ListBox)
 
-	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("listBoxPreferredClasses")
-	contentTabVentanas.listBoxPreferredClasses = obj.(*gtk.ListBox)
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("listBoxIncludeRules")
+	contentTabVentanas.listBoxIncludeRules = obj.(*gtk.ListBox)
 
-	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("listBoxExcludedClasses")
-	contentTabVentanas.listBoxExcludedClasses = obj.(*gtk.ListBox)
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("listBoxExcludeRules")
+	contentTabVentanas.listBoxExcludeRules = obj.(*gtk.ListBox)
 
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("labelCurrentOrder")
 	labelCurrentOrder := obj.(*gtk.Label)
@@ -127,6 +152,12 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 		}()
 	})
 
+	// Button that shows why every open window is or isn't in the rotation
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("buttonExplainWindows")
+	buttonExplainWindows := obj.(*gtk.Button)
+	buttonExplainWindows.SetTooltipText(funcGetStringResource("explain_tooltip"))
+	buttonExplainWindows.Connect("clicked", func(button *gtk.Button) { contentTabVentanas.showDialogExplainWindows() })
+
 	// Container of *gtk.TreeView of opened windows
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("containerTreeViewWindows")
 	containerTreeViewWindows := obj.(*gtk.Box)
@@ -135,9 +166,8 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("containerSectionOrder")
 	containerSectionOrder := obj.(*gtk.Box)
 
-	// Auxiliar slices used when expander expands to check if there was any change in the configuration
-	var listPreferredClassesPrevious []string
-	var listExcludedClassesPrevious []string
+	// Rules when the expander collapses, used when it expands to check if there was any change in the configuration
+	var rulesPrevious string
 
 	// Expander
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("expander")
@@ -150,22 +180,8 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 		containerSectionOrder.SetSensitive(expanded)
 
 		if expanded {
-			clasesPreferidasIguales := strings.Join(
-				contentTabVentanas.listPreferredClasses,
-				",",
-			) == strings.Join(
-				listPreferredClassesPrevious,
-				",",
-			)
-			clasesExcluidasIguales := strings.Join(
-				contentTabVentanas.listExcludedClasses,
-				",",
-			) == strings.Join(
-				listExcludedClassesPrevious,
-				",",
-			)
-			// If there was any change in classes config reset the *gtk.Treeview
-			if !clasesPreferidasIguales || !clasesExcluidasIguales {
+			// If there was any change in the rules reset the *gtk.Treeview
+			if encodeRules(contentTabVentanas.rules) != rulesPrevious {
 				contentTabVentanas.windowList.clear()
 				contentTabVentanas.getActiveWindows(true, true)
 			}
@@ -173,11 +189,7 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 			// Emit signal to start global hotkey listener
 			_, _ = contentTabVentanas.mainGUI.application.Emit(signalControlListener, glib.TYPE_NONE, true, false)
 		} else {
-			listPreferredClassesPrevious = nil
-			listPreferredClassesPrevious = append(listPreferredClassesPrevious, contentTabVentanas.listPreferredClasses...)
-
-			listExcludedClassesPrevious = nil
-			listExcludedClassesPrevious = append(listExcludedClassesPrevious, contentTabVentanas.listExcludedClasses...)
+			rulesPrevious = encodeRules(contentTabVentanas.rules)
 
 			// Emit signal to stop global hotkey listener
 			_, _ = contentTabVentanas.mainGUI.application.Emit(signalControlListener, glib.TYPE_NONE, false, false)
@@ -224,6 +236,13 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 		}()
 	})
 
+	// Button of layout snapshots
+	contentTabVentanas.setupMenuSnapshots()
+
+	// Rotation scope
+	contentTabVentanas.setupComboBoxRotationScope()
+	contentTabVentanas.setupComboBoxFocusOutsideRotation()
+
 	// Button restore default order
 	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("buttonRestoreOrder")
 	buttonRestoreOrder := obj.(*gtk.Button)
@@ -261,58 +280,35 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 		}()
 	})
 
-	// Map containing currently active windows, it is used to query the icon when loading config from file
-	activeWindows := map[string]window{}
-	for _, activeWindow := range listWindows(true) {
-		activeWindow.class = getClass(activeWindow.class)
-		activeWindows[activeWindow.class] = activeWindow
+	// Rules from config file, the preferred/excluded classes of old config files are converted to rules
+	rules, err := contentTabVentanas.mainGUI.loadRules()
+	if err != nil {
+		fmt.Println("ERROR READING RULES FROM CONFIG FILE: ", err)
+	}
+	for _, rule := range rules {
+		contentTabVentanas.addRule(rule, false)
 	}
 
-	/*
-		Anonymous function that load the preferred/excluded classes from config file and add them to their
-		associated list and *gtk.ListBox.
-
-		Param:
-			- option: Option to search from config file
-			- list: List where to add the class
-			- box: *gtk.ListBox where to add the class
-	*/
-	getClasses := func(option string, list *[]string, box *gtk.ListBox) {
-		result, _ := contentTabVentanas.mainGUI.application.Emit(
-			signalGetConfig,
-			glib.TYPE_STRING,
-			sectionClasses,
-			option,
+	// Buttons to add a new rule
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("buttonAddIncludeRule")
+	buttonAddIncludeRule := obj.(*gtk.Button)
+	buttonAddIncludeRule.SetTooltipText(funcGetStringResource("gui_add_include_rule_tooltip"))
+	buttonAddIncludeRule.Connect("clicked", func(button *gtk.Button) {
+		contentTabVentanas.addRule(
+			&windowRule{action: ruleActionInclude, field: ruleFieldClass, mode: ruleModeExact},
+			true,
 		)
-		classString := result.(string)
-		if len(classString) > 0 {
-			for _, class := range strings.Split(classString, ",") {
-				class = strings.TrimSpace(class)
-				if !contains(*list, class) {
-					*list = append(*list, class)
-					window := window{class: class}
-					if val, ok := activeWindows[class]; ok {
-						window.icon = val.icon
-					}
-					// Add item to its ListBox, wether it is the one with preferred classes or the one with excluded classes
-					box.Add(contentTabVentanas.createListBoxRow(window, false, box))
-				}
-			}
-		}
-	}
+	})
 
-	// Get preferred classes from config file
-	getClasses(
-		optionPreferredClasses,
-		&contentTabVentanas.listPreferredClasses,
-		contentTabVentanas.listBoxPreferredClasses,
-	)
-	// Get excluded classes from config file
-	getClasses(
-		optionExcludedClasses,
-		&contentTabVentanas.listExcludedClasses,
-		contentTabVentanas.listBoxExcludedClasses,
-	)
+	obj, _ = contentTabVentanas.mainGUI.builder.GetObject("buttonAddExcludeRule")
+	buttonAddExcludeRule := obj.(*gtk.Button)
+	buttonAddExcludeRule.SetTooltipText(funcGetStringResource("gui_add_exclude_rule_tooltip"))
+	buttonAddExcludeRule.Connect("clicked", func(button *gtk.Button) {
+		contentTabVentanas.addRule(
+			&windowRule{action: ruleActionExclude, field: ruleFieldClass, mode: ruleModeExact},
+			true,
+		)
+	})
 
 	// Signal to update the text on the GUI related with current/default order of windows
 	_, _ = glibown.SignalNewV("gui-update-tex-order", glib.TYPE_NONE, 2, glib.TYPE_STRING, glib.TYPE_STRING)
@@ -340,37 +336,24 @@ func (contentTabVentanas *contentTabVentanas) setupContentTabVentanas() {
 	// Signal to enable the button associated with the *gtk.ListBoxRow of active window-classes
 	_, _ = glib.SignalNew("listBoxActiveWindowClasses-enable-button")
 
-	// Get all active windows when the application opens for the first time taking into consideration the config of preferred/excluded classes
+	// Get all active windows when the application opens for the first time taking into consideration the rules
 	contentTabVentanas.getActiveWindows(true, true)
 }
 
 // Get current active window-classes and add them to the *gtk.ListBox "listBoxActiveWindowClasses"
 func (contentTabVentanas *contentTabVentanas) getClassesCurrentWindows() {
-	windowClasses := map[window]bool{}
+	windowClasses := map[string]bool{}
 
 	for _, windowActive := range listWindows(true) {
-		if strings.Contains(windowActive.class, contentTabVentanas.mainGUI.application.GetApplicationID()) ||
-			windowActive.desktop == -1 || windowClasses[windowActive] {
-			continue
-		}
-		alreadyAdded := false
-		for windowKlass := range windowClasses {
-			if strings.Contains(windowActive.class, windowKlass.class) {
-				alreadyAdded = true
-				break
-			}
-		}
-		if alreadyAdded {
+		if getWindowSkip(windowActive, contentTabVentanas.mainGUI.application.GetApplicationID()) != skipNone {
 			continue
 		}
-		windowClasses[windowActive] = true
-	}
-	for windowKlass, active := range windowClasses {
-		if !active {
+		windowActive.class = windowActive.getClass()
+		if windowClasses[windowActive.class] {
 			continue
 		}
-		windowKlass.class = getClass(windowKlass.class)
-		contentTabVentanas.listWindowClass = append(contentTabVentanas.listWindowClass, windowKlass)
+		windowClasses[windowActive.class] = true
+		contentTabVentanas.listWindowClass = append(contentTabVentanas.listWindowClass, windowActive)
 	}
 	sort.Slice(contentTabVentanas.listWindowClass, func(i, j int) bool {
 		return strings.ToLower(
@@ -381,49 +364,31 @@ func (contentTabVentanas *contentTabVentanas) getClassesCurrentWindows() {
 	})
 	for _, windowClass := range contentTabVentanas.listWindowClass {
 		// Add items to main ListBox, showing all currently active window-classes
-		contentTabVentanas.listBoxActiveWindowClasses.Add(contentTabVentanas.createListBoxRow(windowClass, true, nil))
+		contentTabVentanas.listBoxActiveWindowClasses.Add(contentTabVentanas.createListBoxRow(windowClass))
 	}
 }
 
-// Function that get all currently active windows taking into consideration config of preferred/excluded classes
+// Function that get all currently active windows taking into consideration the rules
 func (contentTabVentanas *contentTabVentanas) getActiveWindows(resetCurrentOrder bool, resetDefaultOrder bool) {
 	var validWindows []window
-	for _, windowActive := range listWindows(true) {
-		if strings.Contains(windowActive.class, contentTabVentanas.mainGUI.application.GetApplicationID()) ||
-			windowActive.desktop == -1 {
-			continue
-		}
-		valid := true
-		if len(contentTabVentanas.listPreferredClasses) > 0 {
-			valid = false
-			for _, preferredClass := range contentTabVentanas.listPreferredClasses {
-				if strings.Contains(windowActive.class, preferredClass) {
-					valid = true
-					break
-				}
-			}
-		}
-		if len(contentTabVentanas.listExcludedClasses) > 0 {
-			for _, excludedClass := range contentTabVentanas.listExcludedClasses {
-				if strings.Contains(windowActive.class, excludedClass) {
-					valid = false
-					break
-				}
-			}
-		}
-		if valid {
+	applicationId := contentTabVentanas.mainGUI.application.GetApplicationID()
+	openWindows := listWindows(true)
+	for _, windowActive := range openWindows {
+		if filterWindow(contentTabVentanas.rules, windowActive, applicationId).included {
 			validWindows = append(validWindows, windowActive)
 		}
 	}
 	contentTabVentanas.windowList.windowList = []window{}
 	for index, validWindow := range validWindows {
-		validWindow.class = getClass(validWindow.class)
+		validWindow.class = validWindow.getClass()
 		validWindow.order = index + 1
 		// Add every valid window to the internal window list
 		contentTabVentanas.windowList.windowList = append(contentTabVentanas.windowList.windowList, validWindow)
-		// Add every valid window to the *gtk.TreeView (GUI)
-		contentTabVentanas.windowList.addRow(validWindow, false, nil)
+		// Add every valid window to the *gtk.TreeView (GUI), its transient windows (dialogs) go under it
+		iter := contentTabVentanas.windowList.addRow(validWindow, false, nil)
+		contentTabVentanas.windowList.addTransientRows(validWindow, iter, openWindows)
 	}
+	contentTabVentanas.windowList.treeViewActiveWindows.ExpandAll()
 
 	if !resetCurrentOrder {
 		// If the length of current order and default order are different or if length of current order is 0 then it gets resetted
@@ -456,37 +421,15 @@ func (contentTabVentanas *contentTabVentanas) getActiveWindows(resetCurrentOrder
 	)
 }
 
-/*
-Function that creates a *gtk.ListBoxRow ready to be added to a *gtk.ListBox.
-
-Parameters:
-
-  - windowClass: windowClass
-
-  - add:   Handles the functionallity of the button inside the *gtk.ListBoxRow. If true, the button "add" is shown else "delete"
-
-  - box:   *gtk.ListBox where to delete items when the callback of button "delete" is triggered. It can be nil
-*/
-func (contentTabVentanas *contentTabVentanas) createListBoxRow(
-	windowClass window,
-	add bool,
-	box *gtk.ListBox,
-) *gtk.ListBoxRow {
+// Function that creates the *gtk.ListBoxRow of a class of the open windows, with a menu to add a rule for the class
+func (contentTabVentanas *contentTabVentanas) createListBoxRow(windowClass window) *gtk.ListBoxRow {
 	builder := getNewBuilder()
 
-	// Config anonymous function to assign all UI strings to appropriate locale
-	initLocale := func() {
-		obj, _ := builder.GetObject("labelButtonAddClassToListBox")
-		labelButtonAddClassToListBox := obj.(*gtk.Label)
-		labelButtonAddClassToListBox.SetMarkup(funcGetStringResource("add"))
+	obj, _ := builder.GetObject("labelButtonAddClassToListBox")
+	labelButtonAddClassToListBox := obj.(*gtk.Label)
+	labelButtonAddClassToListBox.SetMarkup(funcGetStringResource("add"))
 
-		obj, _ = builder.GetObject("labelButtonDeleteClassFromListBox")
-		labelButtonDeleteClassFromListBox := obj.(*gtk.Label)
-		labelButtonDeleteClassFromListBox.SetMarkup(funcGetStringResource("delete"))
-	}
-	initLocale()
-
-	obj, _ := builder.GetObject("listBoxRowClass")
+	obj, _ = builder.GetObject("listBoxRowClass")
 	listboxRow := obj.(*gtk.ListBoxRow)
 	listboxRow.SetName(windowClass.class)
 
@@ -501,200 +444,169 @@ func (contentTabVentanas *contentTabVentanas) createListBoxRow(
 		imageRow.SetFromPixbuf(windowClass.icon)
 	}
 
-	var button_ *gtk.Widget
-	if add { // Show button "Add"
-		obj, _ = builder.GetObject("buttonAddToListBox")
-		button := obj.(*gtk.MenuButton)
-		button.SetTooltipText(funcGetStringResource("gui_tooltip_add_class_to_listbox"))
-		button_ = &button.Widget
-
-		menu, _ := gtk.MenuNew()
-		menu.SetVAlign(gtk.ALIGN_CENTER)
-
-		addToPreferredClasses, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_class_add_to_preferred_list"))
-		addToPreferredClasses.Connect("activate", func(menuItem *gtk.MenuItem) {
-			contentTabVentanas.addITemToListBox(
-				windowClass,
-				optionPreferredClasses,
-				&contentTabVentanas.listPreferredClasses,
-				button_,
-				contentTabVentanas.listBoxPreferredClasses,
+	obj, _ = builder.GetObject("buttonAddToListBox")
+	button := obj.(*gtk.MenuButton)
+	button.SetTooltipText(funcGetStringResource("gui_tooltip_add_class_to_listbox"))
+
+	menu, _ := gtk.MenuNew()
+	menu.SetVAlign(gtk.ALIGN_CENTER)
+	for _, action := range []ruleAction{ruleActionInclude, ruleActionExclude} {
+		menuItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_class_add_" + string(action) + "_rule"))
+		menuItem.Connect("activate", func(menuItem *gtk.MenuItem) {
+			contentTabVentanas.addRule(
+				&windowRule{action: action, field: ruleFieldClass, mode: ruleModeExact, pattern: windowClass.class},
+				true,
 			)
 		})
-
-		addClasesExcluidas, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_class_add_to_excluded_list"))
-		addClasesExcluidas.Connect("activate", func(menuItem *gtk.MenuItem) {
-			contentTabVentanas.addITemToListBox(
-				windowClass,
-				optionExcludedClasses,
-				&contentTabVentanas.listExcludedClasses,
-				button_,
-				contentTabVentanas.listBoxExcludedClasses,
-			)
-		})
-
-		menu.Add(addToPreferredClasses)
-		menu.Add(addClasesExcluidas)
-		menu.ShowAll()
-		button.SetPopup(menu)
-
-		// Anonymous function that checks if the list of preferred/excluded classes
-		// already contains windowClass so make it non-sensitive
-		checkButton := func() {
-			button.SetSensitive(
-				!contains(contentTabVentanas.listPreferredClasses, windowClass.class) &&
-					!contains(contentTabVentanas.listExcludedClasses, windowClass.class),
-			)
-		}
-		checkButton()
-
-		// Handler of signal
-		listboxRow.Connect("listBoxActiveWindowClasses-enable-button", func(row *gtk.ListBoxRow) { checkButton() })
-	} else { // Show button "Delete"
-		obj, _ = builder.GetObject("buttonDeleteFromListBox")
-		button := obj.(*gtk.Button)
-		button_ = &button.Widget
-
-		textTooltip := funcGetStringResource("gui_class_delete_from_preferred_list")
-		associatedList := &contentTabVentanas.listPreferredClasses
-		name, _ := box.GetName()
-		if name == "listBoxExcludedClasses" {
-			textTooltip = funcGetStringResource("gui_class_delete_from_excluded_list")
-			associatedList = &contentTabVentanas.listExcludedClasses
-		}
-		button.SetTooltipText(textTooltip)
-		button.Connect("clicked", func(button *gtk.Button) {
-			option := optionPreferredClasses
-			if name == "listBoxExcludedClasses" {
-				option = optionExcludedClasses
-			}
-			contentTabVentanas.deleteItemFromListBox(windowClass.class, option, associatedList, button_, box, listboxRow)
-		})
+		menu.Add(menuItem)
+	}
+	menu.ShowAll()
+	button.SetPopup(menu)
+
+	// Anonymous function that checks if there's already an exact rule for the class so make it non-sensitive
+	checkButton := func() {
+		button.SetSensitive(!slices.ContainsFunc(contentTabVentanas.rules, func(rule *windowRule) bool {
+			return rule.field == ruleFieldClass && rule.mode == ruleModeExact && rule.pattern == windowClass.class
+		}))
 	}
-	button_.SetVisible(true) // Both buttons are not visible, only the one needed is shown
+	checkButton()
+
+	// Handler of signal
+	listboxRow.Connect("listBoxActiveWindowClasses-enable-button", func(row *gtk.ListBoxRow) { checkButton() })
 	return listboxRow
 }
 
 /*
-Function that adds an item to the *gtk.ListBox of preferred/excluded classes.
+Function that adds a rule to the *gtk.ListBox of include/exclude rules.
 
 Parameters:
-  - windowClass: window containing the desired class
-  - option: Option from config file to edit
-  - associatedList: List to edit
-  - associatedButton: Button with the item's functionallity
-  - targetListBox: *gtk.ListBox target
+  - rule: Rule to add
+  - save: Whether the rule is a new one and the rules have to be saved in config file
 */
-func (contentTabVentanas *contentTabVentanas) addITemToListBox(
-	windowClass window,
-	option string,
-	associatedList *[]string,
-	associatedButton *gtk.Widget,
-	targetListBox *gtk.ListBox,
-) {
-	if contains(*associatedList, windowClass.class) {
-		return
+func (contentTabVentanas *contentTabVentanas) addRule(rule *windowRule, save bool) {
+	contentTabVentanas.rules = append(contentTabVentanas.rules, rule)
+	targetListBox := contentTabVentanas.listBoxIncludeRules
+	if rule.action == ruleActionExclude {
+		targetListBox = contentTabVentanas.listBoxExcludeRules
+	}
+	listBoxRow, entryPattern := contentTabVentanas.createListBoxRowRule(rule, targetListBox)
+	targetListBox.Add(listBoxRow)
+	listBoxRow.ShowAll()
+	if save {
+		contentTabVentanas.saveRules()
+		if len(rule.pattern) == 0 {
+			entryPattern.GrabFocus()
+		}
 	}
-	*associatedList = append(*associatedList, windowClass.class)
-
-	// Update config file when adding a class
-	result, _ := contentTabVentanas.mainGUI.application.Emit(
-		signalUpdateConfig,
-		glib.TYPE_BOOLEAN,
-		sectionClasses,
-		option,
-		strings.Join(*associatedList, ","),
-	)
-	go func() {
-		glib.IdleAdd(func() { associatedButton.SetSensitive(false) })
-		time.Sleep(time.Second / 3)
-		glib.IdleAdd(func() {
-			targetListBox.Add(contentTabVentanas.createListBoxRow(windowClass, false, targetListBox))
-			if !result.(bool) {
-				msg2 := funcGetStringResource("gui_class_error_add_to_preferred_listbox")
-				if option == optionExcludedClasses {
-					msg2 = funcGetStringResource("gui_class_error_add_to_excluded_listbox")
-				}
-				contentTabVentanas.mainGUI.showMessageDialog(
-					gtk.MESSAGE_ERROR,
-					funcGetStringResource("config_error_update_file"),
-					msg2,
-				)
-			}
-		})
-	}()
 }
 
 /*
-Function that deletes an item from the *gtk.ListBox of preferred/excluded classes.
+Function that creates the *gtk.ListBoxRow to edit a rule: priority, field, mode and pattern. Every change is saved in
+config file.
 
 Parameters:
-  - class: Class to delete
-  - option: Option from config file to update
-  - associatedList: Associated list to update
-  - associatedListBox: *gtk.ListBox where to delete the item
-  - associatedListBoxRow: *gtk.ListBoxRow to delete
+  - rule: Rule edited by the row
+  - box: *gtk.ListBox where to delete the row when the rule is deleted
+
+Returns:
+  - The *gtk.ListBoxRow
+  - The *gtk.Entry of the pattern
 */
-func (contentTabVentanas *contentTabVentanas) deleteItemFromListBox(
-	class string,
-	option string,
-	associatedList *[]string,
-	associatedButton *gtk.Widget,
-	associatedListBox *gtk.ListBox,
-	associatedListBoxRow *gtk.ListBoxRow,
-) {
-	*associatedList = removeItem(*associatedList, class)
-	// Update config file when removing class
-	result, _ := contentTabVentanas.mainGUI.application.Emit(
-		signalUpdateConfig,
-		glib.TYPE_BOOLEAN,
-		sectionClasses,
-		option,
-		strings.Join(*associatedList, ","),
-	)
-	go func() {
-		glib.IdleAdd(func() { associatedButton.SetSensitive(false) })
-		time.Sleep(time.Second / 3)
-		glib.IdleAdd(func() {
-			var associatedItem *gtk.Widget
-			isClassCurrentlyShown := false
-			for _, windowKlass := range contentTabVentanas.listWindowClass {
-				if strings.Contains(windowKlass.class, class) || strings.Contains(class, windowKlass.class) {
-					isClassCurrentlyShown = true
-					break
-				}
-			}
-			// If class is currently visible in the *gtk.ListBox of active classes emit signal
-			// to activate its button again
-			if isClassCurrentlyShown {
-				for list := contentTabVentanas.listBoxActiveWindowClasses.GetChildren(); list != nil; list = list.Next() {
-					item := list.Data().(*gtk.Widget)
-					if name, err := item.GetName(); err == nil {
-						if name == class {
-							associatedItem = item
-							break
-						}
-					}
-				}
-			}
-			// Remove *gtk.ListBoxRow from desired *gtk.ListBox
-			associatedListBox.Remove(associatedListBoxRow)
-			if associatedItem != nil {
-				// Emit signal to enable button if item is in *gtk.ListBox of active window-classes
-				_, _ = associatedItem.Emit("listBoxActiveWindowClasses-enable-button", glib.TYPE_NONE)
-			}
-			if !result.(bool) {
-				msg2 := funcGetStringResource("gui_class_error_update_preferred_listbox")
-				if option == optionExcludedClasses {
-					msg2 = funcGetStringResource("gui_class_error_update_excluded_listbox")
-				}
-				contentTabVentanas.mainGUI.showMessageDialog(
-					gtk.MESSAGE_ERROR,
-					funcGetStringResource("config_error_update_file"),
-					msg2,
-				)
-			}
-			associatedButton.SetSensitive(true)
-		})
-	}()
+func (contentTabVentanas *contentTabVentanas) createListBoxRowRule(
+	rule *windowRule,
+	box *gtk.ListBox,
+) (*gtk.ListBoxRow, *gtk.Entry) {
+	boxRule, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	boxRule.SetMarginTop(3)
+	boxRule.SetMarginBottom(3)
+	boxRule.SetMarginStart(5)
+	boxRule.SetMarginEnd(5)
+
+	spinButtonPriority, _ := gtk.SpinButtonNewWithRange(-99, 99, 1)
+	spinButtonPriority.SetValue(float64(rule.priority))
+	spinButtonPriority.SetTooltipText(funcGetStringResource("gui_rule_priority_tooltip"))
+	spinButtonPriority.Connect("value-changed", func(spinButton *gtk.SpinButton) {
+		rule.priority = spinButton.GetValueAsInt()
+		contentTabVentanas.saveRules()
+	})
+	boxRule.PackStart(spinButtonPriority, false, false, 0)
+
+	comboBoxField, _ := gtk.ComboBoxTextNew()
+	for _, field := range ruleFields {
+		comboBoxField.Append(string(field), funcGetStringResource("gui_rule_field_"+string(field)))
+	}
+	comboBoxField.SetActiveID(string(rule.field))
+	comboBoxField.SetTooltipText(funcGetStringResource("gui_rule_field_tooltip"))
+	boxRule.PackStart(comboBoxField, false, false, 0)
+
+	comboBoxMode, _ := gtk.ComboBoxTextNew()
+	for _, mode := range ruleModes {
+		comboBoxMode.Append(string(mode), funcGetStringResource("gui_rule_mode_"+string(mode)))
+	}
+	comboBoxMode.SetActiveID(string(rule.mode))
+	comboBoxMode.SetTooltipText(funcGetStringResource("gui_rule_mode_tooltip"))
+	boxRule.PackStart(comboBoxMode, false, false, 0)
+
+	entryPattern, _ := gtk.EntryNew()
+	entryPattern.SetText(rule.pattern)
+	entryPattern.SetTooltipText(funcGetStringResource("gui_rule_pattern_tooltip"))
+	boxRule.PackStart(entryPattern, true, true, 0)
+
+	// Anonymous function that shows an icon in the entry of the pattern when it's not valid
+	checkPattern := func() {
+		if err := rule.compile(); err != nil {
+			entryPattern.SetIconFromIconName(gtk.ENTRY_ICON_SECONDARY, "dialog-warning")
+			entryPattern.SetIconTooltipText(
+				gtk.ENTRY_ICON_SECONDARY,
+				fmt.Sprintf(funcGetStringResource("gui_rule_error_pattern"), err),
+			)
+			return
+		}
+		entryPattern.RemoveIcon(gtk.ENTRY_ICON_SECONDARY)
+	}
+	checkPattern()
+
+	comboBoxField.Connect("changed", func(comboBox *gtk.ComboBoxText) {
+		rule.field = ruleField(comboBox.GetActiveID())
+		contentTabVentanas.saveRules()
+	})
+	comboBoxMode.Connect("changed", func(comboBox *gtk.ComboBoxText) {
+		rule.mode = ruleMode(comboBox.GetActiveID())
+		checkPattern()
+		contentTabVentanas.saveRules()
+	})
+	entryPattern.Connect("changed", func(entry *gtk.Entry) {
+		rule.pattern, _ = entry.GetText()
+		checkPattern()
+		contentTabVentanas.saveRules()
+	})
+
+	listBoxRow, _ := gtk.ListBoxRowNew()
+
+	buttonDelete, _ := gtk.ButtonNewFromIconName("list-remove", gtk.ICON_SIZE_BUTTON)
+	buttonDelete.SetTooltipText(funcGetStringResource("gui_rule_delete_tooltip"))
+	buttonDelete.Connect("clicked", func(button *gtk.Button) {
+		contentTabVentanas.rules = slices.DeleteFunc(contentTabVentanas.rules, func(r *windowRule) bool { return r == rule })
+		box.Remove(listBoxRow)
+		contentTabVentanas.saveRules()
+	})
+	boxRule.PackEnd(buttonDelete, false, false, 0)
+
+	listBoxRow.Add(boxRule)
+	return listBoxRow, entryPattern
+}
+
+// Function that saves the rules in config file and enables again the buttons of the classes without an exact rule
+func (contentTabVentanas *contentTabVentanas) saveRules() {
+	if !contentTabVentanas.mainGUI.saveRules(contentTabVentanas.rules) {
+		contentTabVentanas.mainGUI.showMessageDialog(
+			gtk.MESSAGE_ERROR,
+			funcGetStringResource("config_error_update_file"),
+			funcGetStringResource("gui_rule_error_save"),
+		)
+	}
+	// Emit signal to update the button of every item in *gtk.ListBox of active window-classes
+	contentTabVentanas.listBoxActiveWindowClasses.GetChildren().Foreach(func(item any) {
+		_, _ = item.(*gtk.Widget).Emit("listBoxActiveWindowClasses-enable-button", glib.TYPE_NONE)
+	})
 }
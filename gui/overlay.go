@@ -0,0 +1,231 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/gotk3/gotk3/pango"
+)
+
+// Section and options from config file related with the overlay shown while cycling
+const (
+	sectionOverlay       = "overlay"
+	optionOverlayEnabled = "enabled"
+	optionOverlayTimeout = "timeout" // Milliseconds the overlay stays visible after the last move
+
+	defaultOverlayTimeout = 1500
+	overlayIconSize       = 32
+	overlayMaxRows        = 15 // Rows shown at most, the ones around the selected window
+)
+
+// switcherOverlay Popup shown over the active monitor while cycling with the windows of the rotation
+type switcherOverlay struct {
+	window      *gtk.Window
+	labelHeader *gtk.Label
+	listBox     *gtk.ListBox
+	hideTimeout glib.SourceHandle
+	icons       map[string]*gdk.Pixbuf // Icons of the windows of the order shown. Structure: key: Id, value: Icon
+}
+
+var (
+	overlay        *switcherOverlay
+	overlayEnabled = true
+	overlayTimeout = uint(defaultOverlayTimeout)
+)
+
+// Function that loads the config of the overlay from config file
+func (mainGUI *MainGUI) loadOverlayConfig() {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionOverlay, optionOverlayEnabled)
+	if enabled, err := strconv.ParseBool(result.(string)); err == nil {
+		overlayEnabled = enabled
+	}
+
+	result, _ = mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionOverlay, optionOverlayTimeout)
+	if timeout, err := strconv.Atoi(result.(string)); err == nil && timeout > 0 {
+		overlayTimeout = uint(timeout)
+	}
+}
+
+// Function that creates the popup of the overlay
+func newSwitcherOverlay() *switcherOverlay {
+	window, _ := gtk.WindowNew(gtk.WINDOW_POPUP)
+	window.SetName("switcherOverlay")
+	window.SetTypeHint(gdk.WINDOW_TYPE_HINT_NOTIFICATION)
+	window.SetKeepAbove(true)
+	window.SetAcceptFocus(false)
+	window.SetResizable(false)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 8)
+	box.SetMarginTop(12)
+	box.SetMarginBottom(12)
+	box.SetMarginStart(12)
+	box.SetMarginEnd(12)
+
+	labelHeader, _ := gtk.LabelNew("")
+	box.PackStart(labelHeader, false, false, 0)
+
+	listBox, _ := gtk.ListBoxNew()
+	listBox.SetSelectionMode(gtk.SELECTION_NONE)
+	box.PackStart(listBox, false, false, 0)
+	box.ShowAll()
+	window.Add(box)
+
+	// The row of the selected window is highlighted with the colors of the theme
+	cssProvider, _ := gtk.CssProviderNew()
+	_ = cssProvider.LoadFromData(
+		"#switcherOverlay { border: 1px solid @borders; border-radius: 6px; } " +
+			"#switcherOverlay row.selected-window { background-color: @theme_selected_bg_color; " +
+			"color: @theme_selected_fg_color; border-radius: 4px; }",
+	)
+	screen, _ := gdk.ScreenGetDefault()
+	gtk.AddProviderForScreen(screen, cssProvider, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+
+	return &switcherOverlay{window: window, labelHeader: labelHeader, listBox: listBox, icons: map[string]*gdk.Pixbuf{}}
+}
+
+/*
+Function that shows the overlay with the windows of the rotation and the selected one highlighted. It can be called
+from any goroutine.
+
+Parameters:
+  - windows: Windows of the rotation
+  - selected: Index of the selected window
+  - autoHide: Whether the overlay hides itself after the timeout, otherwise it stays until hideOverlay is called
+*/
+func showOverlay(windows []window, selected int, autoHide bool) {
+	if !overlayEnabled || len(windows) == 0 || selected < 0 || selected >= len(windows) {
+		return
+	}
+	windows = append([]window(nil), windows...)
+	glib.IdleAdd(func() {
+		if overlay == nil {
+			overlay = newSwitcherOverlay()
+		}
+		overlay.update(windows, selected)
+		centerOnActiveMonitor(overlay.window)
+		overlay.window.Show()
+
+		if overlay.hideTimeout != 0 {
+			glib.SourceRemove(overlay.hideTimeout)
+			overlay.hideTimeout = 0
+		}
+		if autoHide {
+			overlay.hideTimeout = glib.TimeoutAdd(overlayTimeout, func() bool {
+				overlay.hideTimeout = 0
+				overlay.window.Hide()
+				return false
+			})
+		}
+	})
+}
+
+// Function that hides the overlay. It can be called from any goroutine
+func hideOverlay() {
+	glib.IdleAdd(func() {
+		if overlay == nil {
+			return
+		}
+		if overlay.hideTimeout != 0 {
+			glib.SourceRemove(overlay.hideTimeout)
+			overlay.hideTimeout = 0
+		}
+		overlay.window.Hide()
+	})
+}
+
+// Function that fills the overlay with the windows around the selected one
+func (overlay *switcherOverlay) update(windows []window, selected int) {
+	overlay.listBox.GetChildren().Foreach(func(item any) { item.(*gtk.Widget).Destroy() })
+	overlay.labelHeader.SetMarkup(fmt.Sprintf("<b>%d / %d</b>", selected+1, len(windows)))
+
+	first := max(0, min(selected-overlayMaxRows/2, len(windows)-overlayMaxRows))
+	last := min(len(windows), first+overlayMaxRows)
+	for index := first; index < last; index++ {
+		window := windows[index]
+		box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+		box.SetMarginTop(4)
+		box.SetMarginBottom(4)
+		box.SetMarginStart(8)
+		box.SetMarginEnd(8)
+
+		labelNumber, _ := gtk.LabelNew(strconv.Itoa(index + 1))
+		labelNumber.SetWidthChars(len(strconv.Itoa(len(windows))))
+		box.PackStart(labelNumber, false, false, 0)
+
+		if icon := overlay.getIcon(window); icon != nil {
+			image, _ := gtk.ImageNewFromPixbuf(icon)
+			box.PackStart(image, false, false, 0)
+		}
+
+		labelTitle, _ := gtk.LabelNew("")
+		labelTitle.SetMarkup(fmt.Sprintf(
+			"%s <small>(%s)</small>",
+			glib.MarkupEscapeText(window.title),
+			glib.MarkupEscapeText(window.getClass()),
+		))
+		labelTitle.SetEllipsize(pango.ELLIPSIZE_END)
+		labelTitle.SetMaxWidthChars(60)
+		labelTitle.SetHAlign(gtk.ALIGN_START)
+		box.PackStart(labelTitle, true, true, 0)
+
+		row, _ := gtk.ListBoxRowNew()
+		row.Add(box)
+		if index == selected {
+			styleContext, _ := row.GetStyleContext()
+			styleContext.AddClass("selected-window")
+		}
+		overlay.listBox.Add(row)
+	}
+	overlay.listBox.ShowAll()
+	overlay.window.Resize(1, 1) // Shrinks to the size of the new content
+
+	// Only the icons of the windows of the order shown are kept, the closed windows are forgotten
+	for windowId := range overlay.icons {
+		if !slices.ContainsFunc(windows, func(w window) bool { return w.id == windowId }) {
+			delete(overlay.icons, windowId)
+		}
+	}
+}
+
+// Function that returns the icon of a window, it's read from the X server only the first time
+func (overlay *switcherOverlay) getIcon(window window) *gdk.Pixbuf {
+	if icon, exists := overlay.icons[window.id]; exists {
+		return icon
+	}
+	var icon *gdk.Pixbuf
+	if originalIcon := xConn.GetWindowIcon(getXWindow(window.id)); originalIcon != nil {
+		icon, _ = originalIcon.ScaleSimple(overlayIconSize, overlayIconSize, gdk.INTERP_HYPER)
+	}
+	if icon == nil {
+		icon = window.icon
+	}
+	overlay.icons[window.id] = icon
+	return icon
+}
+
+// Function that centers a window on the monitor of the active window, or on the primary monitor
+func centerOnActiveMonitor(window *gtk.Window) {
+	display, err := gdk.DisplayGetDefault()
+	if err != nil {
+		return
+	}
+	var monitor *gdk.Monitor
+	if result, activeWindow := xConn.GetActiveWindow(); result {
+		if geometry, err := xConn.GetWindowGeometry(activeWindow); geometry != nil && err == nil {
+			frame := geometry.Frame
+			monitor, _ = display.GetMonitorAtPoint(frame.X+frame.Width/2, frame.Y+frame.Height/2)
+		}
+	}
+	if monitor == nil {
+		if monitor, err = display.GetPrimaryMonitor(); monitor == nil || err != nil {
+			return
+		}
+	}
+	x, y, width, height := monitor.GetWorkarea().GetRectangleInt()
+	_, preferredSize := window.GetPreferredSize()
+	window.Move(x+(width-preferredSize.Width)/2, y+(height-preferredSize.Height)/2)
+}
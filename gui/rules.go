@@ -0,0 +1,322 @@
+package gui
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// ruleField Property of a window matched by a rule
+type ruleField string
+
+// ruleMode How the pattern of a rule is matched
+type ruleMode string
+
+// ruleAction What a rule does with the windows it matches
+type ruleAction string
+
+const (
+	ruleFieldClass      ruleField = "class"      // Class of WM_CLASS, or the class shown in the lists of classes
+	ruleFieldInstance   ruleField = "instance"   // Instance (name) of WM_CLASS
+	ruleFieldTitle      ruleField = "title"      // _NET_WM_NAME or WM_NAME
+	ruleFieldRole       ruleField = "role"       // WM_WINDOW_ROLE
+	ruleFieldPID        ruleField = "pid"        // _NET_WM_PID
+	ruleFieldExecutable ruleField = "executable" // Path or name of the executable of _NET_WM_PID
+	ruleFieldDesktop    ruleField = "desktop"    // Number or name of the desktop
+
+	ruleModeExact ruleMode = "exact"
+	ruleModeGlob  ruleMode = "glob" // "*" matches any text and "?" any character
+	ruleModeRegex ruleMode = "regex"
+
+	ruleActionInclude ruleAction = "include"
+	ruleActionExclude ruleAction = "exclude"
+
+	// Section and option from config file with the rules
+	sectionRules = "rules"
+	optionRules  = "rules"
+)
+
+var (
+	ruleFields = []ruleField{
+		ruleFieldClass,
+		ruleFieldInstance,
+		ruleFieldTitle,
+		ruleFieldRole,
+		ruleFieldPID,
+		ruleFieldExecutable,
+		ruleFieldDesktop,
+	}
+	ruleModes = []ruleMode{ruleModeExact, ruleModeGlob, ruleModeRegex}
+)
+
+// windowRule Rule that includes or excludes the windows whose field matches a pattern, it's saved in the config file
+// with encodeConfigFields
+type windowRule struct {
+	action   ruleAction
+	field    ruleField
+	mode     ruleMode
+	pattern  string
+	priority int            // The rules with a higher priority are applied first
+	regex    *regexp.Regexp // Compiled pattern of the modes glob and regex, nil if the pattern is not valid
+}
+
+// Function that returns the rule as a string to be saved in the config file
+func (rule windowRule) encode() string {
+	return encodeConfigFields([]string{
+		string(rule.action),
+		string(rule.field),
+		string(rule.mode),
+		rule.pattern,
+		strconv.Itoa(rule.priority),
+	})
+}
+
+// Function that parses a rule saved in the config file
+func decodeWindowRule(value string) (*windowRule, error) {
+	fields, err := decodeConfigFields(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the rule \"%s\" is not valid", value)
+	}
+	priority, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return nil, err
+	}
+	rule := &windowRule{
+		action:   ruleAction(fields[0]),
+		field:    ruleField(fields[1]),
+		mode:     ruleMode(fields[2]),
+		pattern:  fields[3],
+		priority: priority,
+	}
+	if rule.action != ruleActionInclude && rule.action != ruleActionExclude {
+		return nil, fmt.Errorf("the action of the rule \"%s\" is not valid", value)
+	}
+	if !slices.Contains(ruleFields, rule.field) || !slices.Contains(ruleModes, rule.mode) {
+		return nil, fmt.Errorf("the field or mode of the rule \"%s\" is not valid", value)
+	}
+	_ = rule.compile() // A rule with an invalid pattern is kept so it can be fixed, it doesn't match any window
+	return rule, nil
+}
+
+// Function that compiles the pattern of the rule, it must be called every time the pattern or the mode change
+func (rule *windowRule) compile() error {
+	rule.regex = nil
+	var expression string
+	switch rule.mode {
+	case ruleModeExact:
+		return nil
+	case ruleModeGlob:
+		expression = regexp.QuoteMeta(rule.pattern)
+		expression = strings.ReplaceAll(expression, `\*`, ".*")
+		expression = strings.ReplaceAll(expression, `\?`, ".")
+	case ruleModeRegex:
+		expression = rule.pattern
+	}
+	regex, err := regexp.Compile("^(?:" + expression + ")$")
+	if err != nil {
+		return err
+	}
+	rule.regex = regex
+	return nil
+}
+
+// Function that returns true if the pattern of the rule is valid and not empty
+func (rule *windowRule) isValid() bool {
+	return len(rule.pattern) > 0 && (rule.mode == ruleModeExact || rule.regex != nil)
+}
+
+// Function that returns true if any of the values matches the pattern of the rule
+func (rule *windowRule) matches(values []string) bool {
+	if !rule.isValid() {
+		return false
+	}
+	for _, value := range values {
+		if rule.mode == ruleModeExact {
+			if value == rule.pattern {
+				return true
+			}
+		} else if rule.regex.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowProperties Properties of a window matched by the rules, the ones that need extra requests are read on demand
+type windowProperties struct {
+	window window
+	values map[ruleField][]string
+}
+
+// Constructor windowProperties, the instance and the class are the ones of WM_CLASS
+func newWindowProperties(window window) *windowProperties {
+	properties := &windowProperties{window: window, values: map[ruleField][]string{}}
+	properties.values[ruleFieldClass] = []string{window.wmClass, window.getClass()}
+	properties.values[ruleFieldInstance] = []string{window.wmInstance}
+	properties.values[ruleFieldTitle] = []string{window.title}
+	properties.values[ruleFieldDesktop] = []string{strconv.Itoa(window.desktop), window.desktopName}
+	return properties
+}
+
+// Function that returns the values of a field of the window
+func (properties *windowProperties) get(field ruleField) []string {
+	if values, exists := properties.values[field]; exists {
+		return values
+	}
+	var values []string
+	xWindow := getXWindow(properties.window.id)
+	switch field {
+	case ruleFieldRole:
+		if role, err := xConn.GetWindowProperty(xWindow, "WM_WINDOW_ROLE"); err == nil && role != nil {
+			values = role.GetString()
+		}
+	case ruleFieldPID, ruleFieldExecutable:
+		pid, err := xConn.GetWindowProperty(xWindow, "_NET_WM_PID")
+		if err != nil || pid == nil || len(pid.GetLong()) == 0 {
+			break
+		}
+		values = []string{strconv.FormatInt(pid.GetLong()[0], 10)}
+		if field == ruleFieldExecutable {
+			executable, err := os.Readlink(fmt.Sprintf("/proc/%s/exe", values[0]))
+			values = nil
+			if err == nil {
+				values = []string{executable, filepath.Base(executable)}
+			}
+		}
+	}
+	properties.values[field] = values
+	return values
+}
+
+/*
+Function that applies the rules to a window. The rules are tried from the highest priority to the lowest, with the
+same priority the exclude rules go first, and the first rule that matches decides. If no rule matches the window is
+included only if there are no include rules.
+
+Returns:
+  - Whether the window is included
+  - The rule that decided, nil if no rule matched
+*/
+func evaluateRules(rules []*windowRule, window window) (bool, *windowRule) {
+	sortedRules := slices.Clone(rules)
+	slices.SortStableFunc(sortedRules, func(first *windowRule, second *windowRule) int {
+		if first.priority != second.priority {
+			return cmp.Compare(second.priority, first.priority)
+		}
+		// "exclude" < "include"
+		return cmp.Compare(first.action, second.action)
+	})
+
+	properties := newWindowProperties(window)
+	hasIncludeRules := false
+	for _, rule := range sortedRules {
+		if rule.matches(properties.get(rule.field)) {
+			return rule.action == ruleActionInclude, rule
+		}
+		hasIncludeRules = hasIncludeRules || rule.action == ruleActionInclude && rule.isValid()
+	}
+	return !hasIncludeRules, nil
+}
+
+// Function that returns the rules as a string to be saved in the config file
+func encodeRules(rules []*windowRule) string {
+	var encodedRules []string
+	for _, rule := range rules {
+		encodedRules = append(encodedRules, rule.encode())
+	}
+	return strings.Join(encodedRules, ",")
+}
+
+/*
+Function that reads the rules from the config file. The preferred and excluded classes saved before the rules existed
+are converted to rules of the class, exact ones.
+
+Parameters:
+  - getConfig: Function that returns the value of an option of the config file, empty if it doesn't exist
+
+Returns:
+  - The rules
+  - Whether any preferred/excluded class was converted to a rule
+  - Possible error or nil, if any rule couldn't be read
+*/
+func parseRules(getConfig func(section string, option string) string) ([]*windowRule, bool, error) {
+	var rules []*windowRule
+	var errs []error
+	for _, value := range strings.Split(getConfig(sectionRules, optionRules), ",") {
+		if len(value) == 0 {
+			continue
+		}
+		rule, err := decodeWindowRule(value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	// Preferred and excluded classes
+	migrated := false
+	for _, option := range []string{optionPreferredClasses, optionExcludedClasses} {
+		action := ruleActionInclude
+		if option == optionExcludedClasses {
+			action = ruleActionExclude
+		}
+		for _, class := range strings.Split(getConfig(sectionClasses, option), ",") {
+			if len(class) > 0 {
+				rules = append(rules, &windowRule{action: action, field: ruleFieldClass, mode: ruleModeExact, pattern: class})
+				migrated = true
+			}
+		}
+	}
+	return rules, migrated, errors.Join(errs...)
+}
+
+// Function that returns the value of an option of the config file
+func (mainGUI *MainGUI) getConfig(section string, option string) string {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, section, option)
+	return result.(string)
+}
+
+/*
+Function that loads the rules from the config file, the preferred and excluded classes converted to rules are removed
+from the config file.
+
+Returns:
+  - The rules
+  - Possible error or nil, if any rule couldn't be read
+*/
+func (mainGUI *MainGUI) loadRules() ([]*windowRule, error) {
+	rules, migrated, err := parseRules(mainGUI.getConfig)
+	if migrated {
+		fmt.Println("(Rules) Preferred and excluded classes converted to rules")
+		if mainGUI.saveRules(rules) {
+			_, _ = mainGUI.application.Emit(signalUpdateConfig, glib.TYPE_BOOLEAN, sectionClasses, optionPreferredClasses, "")
+			_, _ = mainGUI.application.Emit(signalUpdateConfig, glib.TYPE_BOOLEAN, sectionClasses, optionExcludedClasses, "")
+		}
+	}
+	return rules, err
+}
+
+// Function that saves the rules in the config file
+func (mainGUI *MainGUI) saveRules(rules []*windowRule) bool {
+	result, _ := mainGUI.application.Emit(
+		signalUpdateConfig,
+		glib.TYPE_BOOLEAN,
+		sectionRules,
+		optionRules,
+		encodeRules(rules),
+	)
+	saved, _ := result.(bool)
+	return saved
+}
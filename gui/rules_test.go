@@ -0,0 +1,205 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWindowRuleCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    ruleMode
+		pattern string
+		value   string
+		matches bool
+		valid   bool
+	}{
+		{"exact", ruleModeExact, "Firefox", "Firefox", true, true},
+		{"exact is case sensitive", ruleModeExact, "Firefox", "firefox", false, true},
+		{"exact is not a substring", ruleModeExact, "Fire", "Firefox", false, true},
+		{"glob star", ruleModeGlob, "Fire*", "Firefox", true, true},
+		{"glob question mark", ruleModeGlob, "Firefo?", "Firefox", true, true},
+		{"glob question mark is one character", ruleModeGlob, "Fire?", "Firefox", false, true},
+		{"glob matches the whole value", ruleModeGlob, "fox", "Firefox", false, true},
+		{"glob quotes the regex characters", ruleModeGlob, "a.b", "axb", false, true},
+		{"glob keeps the dots", ruleModeGlob, "org.gnome.*", "org.gnome.Nautilus", true, true},
+		{"regex", ruleModeRegex, "Fire(fox|bird)", "Firebird", true, true},
+		{"regex matches the whole value", ruleModeRegex, "fox", "Firefox", false, true},
+		{"regex alternatives are anchored", ruleModeRegex, "a|b", "ab", false, true},
+		{"invalid regex", ruleModeRegex, "Fire(", "Fire(", false, false},
+		{"empty pattern", ruleModeExact, "", "", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := &windowRule{action: ruleActionInclude, field: ruleFieldTitle, mode: test.mode, pattern: test.pattern}
+			err := rule.compile()
+			if test.valid != (err == nil && rule.isValid()) {
+				t.Errorf("valid = %t, want %t (err: %v)", !test.valid, test.valid, err)
+			}
+			if matches := rule.matches([]string{test.value}); matches != test.matches {
+				t.Errorf("matches(%q) = %t, want %t", test.value, matches, test.matches)
+			}
+		})
+	}
+}
+
+func TestDecodeWindowRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  *windowRule
+	}{
+		{
+			"escaped pattern",
+			"exclude;title;glob;Private+%2A+%3B+%2C;3",
+			&windowRule{
+				action:   ruleActionExclude,
+				field:    ruleFieldTitle,
+				mode:     ruleModeGlob,
+				pattern:  "Private * ; ,",
+				priority: 3,
+			},
+		},
+		{
+			"invalid pattern is kept",
+			"include;class;regex;Fire%28;0",
+			&windowRule{action: ruleActionInclude, field: ruleFieldClass, mode: ruleModeRegex, pattern: "Fire("},
+		},
+		{"unknown action", "hide;class;exact;Firefox;0", nil},
+		{"unknown field", "include;colour;exact;Firefox;0", nil},
+		{"unknown mode", "include;class;fuzzy;Firefox;0", nil},
+		{"missing field", "include;class;exact;Firefox", nil},
+		{"invalid priority", "include;class;exact;Firefox;high", nil},
+		{"invalid escape", "include;class;exact;Fire%zz;0", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := decodeWindowRule(test.value)
+			if test.want == nil {
+				if err == nil {
+					t.Errorf("decodeWindowRule(%q) = %+v, want an error", test.value, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeWindowRule(%q) returned the error: %v", test.value, err)
+			}
+			if rule.action != test.want.action || rule.field != test.want.field || rule.mode != test.want.mode ||
+				rule.pattern != test.want.pattern || rule.priority != test.want.priority {
+				t.Errorf("decodeWindowRule(%q) = %+v, want %+v", test.value, rule, test.want)
+			}
+			if encoded := rule.encode(); encoded != test.value {
+				t.Errorf("encode() = %q, want %q", encoded, test.value)
+			}
+		})
+	}
+}
+
+func TestEvaluateRules(t *testing.T) {
+	// Anonymous function that returns a compiled rule
+	funcNewRule := func(action ruleAction, field ruleField, mode ruleMode, pattern string, priority int) *windowRule {
+		rule := &windowRule{action: action, field: field, mode: mode, pattern: pattern, priority: priority}
+		_ = rule.compile()
+		return rule
+	}
+	firefox := window{
+		id:          "1",
+		class:       "Navigator.firefox",
+		wmInstance:  "Navigator",
+		wmClass:     "firefox",
+		title:       "Mozilla Firefox",
+		desktop:     1,
+		desktopName: "Web",
+	}
+	excludeFirefox := funcNewRule(ruleActionExclude, ruleFieldClass, ruleModeExact, "firefox", 0)
+	includeFirefox := funcNewRule(ruleActionInclude, ruleFieldClass, ruleModeExact, "firefox", 0)
+	includeTerminal := funcNewRule(ruleActionInclude, ruleFieldClass, ruleModeExact, "terminal", 0)
+	excludeTerminal := funcNewRule(ruleActionExclude, ruleFieldClass, ruleModeExact, "terminal", 0)
+	includeInvalid := funcNewRule(ruleActionInclude, ruleFieldClass, ruleModeRegex, "(", 0)
+	includeMozilla := funcNewRule(ruleActionInclude, ruleFieldTitle, ruleModeGlob, "Mozilla*", 1)
+	excludeWeb := funcNewRule(ruleActionExclude, ruleFieldDesktop, ruleModeExact, "Web", 0)
+	excludeNavigator := funcNewRule(ruleActionExclude, ruleFieldInstance, ruleModeExact, "Navigator", 0)
+
+	tests := []struct {
+		name     string
+		rules    []*windowRule
+		included bool
+		rule     *windowRule
+	}{
+		{"no rules", nil, true, nil},
+		{"exclude rule", []*windowRule{excludeFirefox}, false, excludeFirefox},
+		{"include rule", []*windowRule{includeFirefox}, true, includeFirefox},
+		{"no rule matches with include rules", []*windowRule{includeTerminal}, false, nil},
+		{"no rule matches with only exclude rules", []*windowRule{excludeTerminal}, true, nil},
+		{"invalid include rules don't count", []*windowRule{includeInvalid}, true, nil},
+		{"exclude first with the same priority", []*windowRule{includeFirefox, excludeFirefox}, false, excludeFirefox},
+		{"higher priority first", []*windowRule{excludeFirefox, includeMozilla}, true, includeMozilla},
+		{"desktop name", []*windowRule{excludeWeb}, false, excludeWeb},
+		{"instance", []*windowRule{excludeNavigator}, false, excludeNavigator},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			included, rule := evaluateRules(test.rules, firefox)
+			if included != test.included || rule != test.rule {
+				t.Errorf("evaluateRules() = %t, %+v, want %t, %+v", included, rule, test.included, test.rule)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string // Structure: key: Section and option joined with ".", value: Value
+		want     []string          // Encoded rules
+		migrated bool
+		hasError bool
+	}{
+		{"empty config", map[string]string{}, nil, false, false},
+		{
+			"rules",
+			map[string]string{"rules.rules": "include;class;exact;firefox;0,exclude;title;glob;%2APrivate%2A;2"},
+			[]string{"include;class;exact;firefox;0", "exclude;title;glob;%2APrivate%2A;2"},
+			false,
+			false,
+		},
+		{
+			"preferred and excluded classes are migrated",
+			map[string]string{
+				"classes.preferred_classes": "firefox,Terminal",
+				"classes.excluded_classes":  "Gimp",
+			},
+			[]string{"include;class;exact;firefox;0", "include;class;exact;Terminal;0", "exclude;class;exact;Gimp;0"},
+			true,
+			false,
+		},
+		{
+			"migrated classes go after the rules",
+			map[string]string{"rules.rules": "exclude;class;exact;firefox;1", "classes.preferred_classes": "firefox"},
+			[]string{"exclude;class;exact;firefox;1", "include;class;exact;firefox;0"},
+			true,
+			false,
+		},
+		{
+			"invalid rules are skipped",
+			map[string]string{"rules.rules": "include;class;exact;firefox;0,hide;class;exact;Gimp;0"},
+			[]string{"include;class;exact;firefox;0"},
+			false,
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, migrated, err := parseRules(func(section string, option string) string {
+				return test.config[section+"."+option]
+			})
+			if migrated != test.migrated || (err != nil) != test.hasError {
+				t.Errorf("parseRules() migrated = %t, err = %v, want %t, error: %t", migrated, err, test.migrated,
+					test.hasError)
+			}
+			if encoded, want := encodeRules(rules), strings.Join(test.want, ","); encoded != want {
+				t.Errorf("parseRules() = %q, want %q", encoded, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package gui
+
+import (
+	"fmt"
+	"sync"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// rotationScope Windows of the current order taken into account when moving between windows
+type rotationScope string
+
+const (
+	rotationScopeAll     rotationScope = "all"     // Windows on every desktop
+	rotationScopeDesktop rotationScope = "desktop" // Windows on the current desktop or on all desktops
+	rotationScopeMonitor rotationScope = "monitor" // Windows on the monitor of the active window
+
+	// Section and option from config file with the rotation scope
+	sectionRotation     = "rotation"
+	optionRotationScope = "scope"
+
+	// Value of "_NET_WM_DESKTOP" of the windows shown on all desktops
+	allDesktops = 0xFFFFFFFF
+)
+
+var (
+	currentRotationScope      = rotationScopeAll
+	currentRotationScopeMutex sync.RWMutex
+)
+
+// scopeFilter Desktop and monitor the windows must be on to be in the rotation scope, read when a hotkey is pressed
+type scopeFilter struct {
+	scope    rotationScope
+	desktop  int            // Current desktop, -1 if unknown
+	monitors []xlib.Monitor // Monitors of the screen
+	monitor  int            // Index of the monitor of the active window, -1 if unknown
+}
+
+// Function that returns the rotation scope
+func getRotationScope() rotationScope {
+	currentRotationScopeMutex.RLock()
+	defer currentRotationScopeMutex.RUnlock()
+	return currentRotationScope
+}
+
+// Function that sets the rotation scope
+func setRotationScope(scope rotationScope) {
+	currentRotationScopeMutex.Lock()
+	defer currentRotationScopeMutex.Unlock()
+	currentRotationScope = scope
+}
+
+// Function that loads the rotation scope from config file
+func (mainGUI *MainGUI) loadRotationScopeConfig() {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionRotation, optionRotationScope)
+	switch scope := rotationScope(result.(string)); scope {
+	case rotationScopeAll, rotationScopeDesktop, rotationScopeMonitor:
+		setRotationScope(scope)
+	case "":
+	default:
+		fmt.Println("ERROR UNKNOWN ROTATION SCOPE IN CONFIG FILE: ", scope)
+	}
+}
+
+// Function that sets up the *gtk.ComboBoxText to choose the rotation scope
+func (contentTabVentanas *contentTabVentanas) setupComboBoxRotationScope() {
+	mainGUI := &contentTabVentanas.mainGUI
+
+	obj, _ := mainGUI.builder.GetObject("comboBoxRotationScope")
+	comboBoxRotationScope := obj.(*gtk.ComboBoxText)
+	comboBoxRotationScope.Append(string(rotationScopeAll), funcGetStringResource("gui_rotation_scope_all"))
+	comboBoxRotationScope.Append(string(rotationScopeDesktop), funcGetStringResource("gui_rotation_scope_desktop"))
+	comboBoxRotationScope.Append(string(rotationScopeMonitor), funcGetStringResource("gui_rotation_scope_monitor"))
+	comboBoxRotationScope.SetActiveID(string(getRotationScope()))
+	comboBoxRotationScope.SetTooltipText(funcGetStringResource("gui_rotation_scope_tooltip"))
+	comboBoxRotationScope.Connect("changed", func(comboBox *gtk.ComboBoxText) {
+		scope := rotationScope(comboBox.GetActiveID())
+		setRotationScope(scope)
+		_, _ = mainGUI.application.Emit(
+			signalUpdateConfig,
+			glib.TYPE_BOOLEAN,
+			sectionRotation,
+			optionRotationScope,
+			string(scope),
+		)
+	})
+}
+
+// Function that creates the filter of the rotation scope with the current desktop and the monitor of the active window
+func newScopeFilter() scopeFilter {
+	filter := scopeFilter{scope: getRotationScope(), desktop: -1, monitor: -1}
+	switch filter.scope {
+	case rotationScopeDesktop:
+		if result, desktop := xConn.GetCurrentDesktop(); result {
+			filter.desktop = desktop
+		}
+	case rotationScopeMonitor:
+		filter.monitors = getMonitors()
+		if result, activeWindow := xConn.GetActiveWindow(); result && activeWindow != xlib.CURRENTWINDOW {
+			if geometry, err := xConn.GetWindowGeometry(activeWindow); geometry != nil && err == nil {
+				filter.monitor = xlib.GetMonitorOf(filter.monitors, geometry.Frame)
+			}
+		}
+		// Without an active window the monitor is the one with the mouse
+		if filter.monitor < 0 {
+			if mouseLocation, err := xConn.GetMouseLocation(); mouseLocation != nil && err == nil {
+				filter.monitor = xlib.GetMonitorAt(filter.monitors, mouseLocation.X, mouseLocation.Y)
+			}
+		}
+	}
+	return filter
+}
+
+// Function that returns true if a window is in the rotation scope, when the desktop or monitor is unknown every
+// window is
+func (filter scopeFilter) contains(window window) bool {
+	switch filter.scope {
+	case rotationScopeDesktop:
+		return filter.desktop < 0 || window.desktop == filter.desktop || window.desktop == -1 ||
+			window.desktop == allDesktops
+	case rotationScopeMonitor:
+		if filter.monitor < 0 {
+			return true
+		}
+		frame, err := xlib.ParseRectangle(window.geometry)
+		if err != nil {
+			geometry, err := xConn.GetWindowGeometry(getXWindow(window.id))
+			if geometry == nil || err != nil {
+				return true
+			}
+			frame = geometry.Frame
+		}
+		return xlib.GetMonitorOf(filter.monitors, frame) == filter.monitor
+	}
+	return true
+}
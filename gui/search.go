@@ -0,0 +1,283 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/gotk3/gotk3/pango"
+)
+
+const (
+	searchPopupWidth     = 560
+	searchPopupMaxHeight = 420
+)
+
+// windowPicker Popup to search a window by its class, title or desktop name and activate it
+type windowPicker struct {
+	window      *gtk.Window
+	searchEntry *gtk.SearchEntry
+	listBox     *gtk.ListBox
+	allWindows  []window // Windows returned by listWindows when the picker was opened
+	windows     []window // Windows matching the search, in the same order as the rows of the *gtk.ListBox
+}
+
+// Instance of the window picker open, only one can be open at a time
+var openPicker *windowPicker
+
+// Function that opens the window picker. Callback of global hotkey
+func (mainGUI *MainGUI) showWindowPicker() {
+	glib.IdleAdd(func() {
+		if openPicker != nil {
+			openPicker.window.Present()
+			return
+		}
+		openPicker = newWindowPicker()
+		openPicker.search("")
+		openPicker.window.ShowAll()
+		centerOnActiveMonitor(openPicker.window)
+
+		// The popup is activated like any other window, the window manager could refuse to focus it otherwise
+		if gdkWindow, err := openPicker.window.GetWindow(); err == nil {
+			xWindow := xlib.Window(gdkWindow.GetXID())
+			go func() {
+				if err := activateWindow(xWindow); err != nil {
+					fmt.Println("ERROR ACTIVATING WINDOW PICKER: ", err)
+				}
+			}()
+		}
+	})
+}
+
+// Function that creates the popup of the window picker with all the windows returned by listWindows
+func newWindowPicker() *windowPicker {
+	picker := &windowPicker{allWindows: listWindows(true)}
+
+	picker.window, _ = gtk.WindowNew(gtk.WINDOW_TOPLEVEL)
+	picker.window.SetTitle(fmt.Sprintf("%s - %s", title, funcGetStringResource("gui_search_windows_title")))
+	picker.window.SetIcon(defaultAppIcon)
+	picker.window.SetDecorated(false)
+	picker.window.SetKeepAbove(true)
+	picker.window.SetSkipTaskbarHint(true)
+	picker.window.SetTypeHint(gdk.WINDOW_TYPE_HINT_DIALOG)
+	picker.window.SetDefaultSize(searchPopupWidth, -1)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 6)
+	box.SetMarginTop(8)
+	box.SetMarginBottom(8)
+	box.SetMarginStart(8)
+	box.SetMarginEnd(8)
+
+	picker.searchEntry, _ = gtk.SearchEntryNew()
+	picker.searchEntry.SetPlaceholderText(funcGetStringResource("gui_search_windows_placeholder"))
+	box.PackStart(picker.searchEntry, false, false, 0)
+
+	scrolledWindow, _ := gtk.ScrolledWindowNew(nil, nil)
+	scrolledWindow.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scrolledWindow.SetPropagateNaturalHeight(true)
+	scrolledWindow.SetMaxContentHeight(searchPopupMaxHeight)
+	picker.listBox, _ = gtk.ListBoxNew()
+	picker.listBox.SetSelectionMode(gtk.SELECTION_BROWSE)
+	scrolledWindow.Add(picker.listBox)
+	box.PackStart(scrolledWindow, true, true, 0)
+	picker.window.Add(box)
+
+	picker.searchEntry.Connect("search-changed", func(entry *gtk.SearchEntry) {
+		query, _ := entry.GetText()
+		picker.search(query)
+	})
+	// Enter activates the selected window
+	picker.searchEntry.Connect("activate", func(entry *gtk.SearchEntry) { picker.activateSelected() })
+	picker.listBox.Connect("row-activated", func(listBox *gtk.ListBox, row *gtk.ListBoxRow) {
+		picker.listBox.SelectRow(row)
+		picker.activateSelected()
+	})
+	// The arrows move the selection while the focus stays on the entry, Escape closes the popup
+	picker.searchEntry.Connect("key-press-event", func(entry *gtk.SearchEntry, event *gdk.Event) bool {
+		switch gdk.EventKeyNewFromEvent(event).KeyVal() {
+		case gdk.KEY_Down:
+			picker.moveSelection(1)
+		case gdk.KEY_Up:
+			picker.moveSelection(-1)
+		case gdk.KEY_Escape:
+			picker.close()
+		default:
+			return false
+		}
+		return true
+	})
+	picker.window.Connect("focus-out-event", func(window *gtk.Window, event *gdk.Event) bool {
+		picker.close()
+		return false
+	})
+	return picker
+}
+
+// Function that fills the *gtk.ListBox with the windows matching a query, the best matches first
+func (picker *windowPicker) search(query string) {
+	picker.listBox.GetChildren().Foreach(func(item any) { item.(*gtk.Widget).Destroy() })
+
+	type match struct {
+		window window
+		score  int
+	}
+	var matches []match
+	for _, window := range picker.allWindows {
+		if score, ok := fuzzyMatchWindow(query, window); ok {
+			matches = append(matches, match{window: window, score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(first match, second match) int { return second.score - first.score })
+
+	picker.windows = nil
+	for _, match := range matches {
+		picker.windows = append(picker.windows, match.window)
+		picker.listBox.Add(createRowWindowPicker(match.window))
+	}
+	picker.listBox.ShowAll()
+	if row := picker.listBox.GetRowAtIndex(0); row != nil {
+		picker.listBox.SelectRow(row)
+	}
+}
+
+// Function that creates the *gtk.ListBoxRow of a window: icon, title, class and desktop name
+func createRowWindowPicker(window window) *gtk.ListBoxRow {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	box.SetMarginTop(4)
+	box.SetMarginBottom(4)
+	box.SetMarginStart(6)
+	box.SetMarginEnd(6)
+
+	if window.icon != nil {
+		image, _ := gtk.ImageNewFromPixbuf(window.icon)
+		box.PackStart(image, false, false, 0)
+	}
+
+	labelTitle, _ := gtk.LabelNew("")
+	labelTitle.SetMarkup(fmt.Sprintf(
+		"%s\n<small>%s — %s</small>",
+		glib.MarkupEscapeText(window.title),
+		glib.MarkupEscapeText(window.class),
+		glib.MarkupEscapeText(window.desktopName),
+	))
+	labelTitle.SetEllipsize(pango.ELLIPSIZE_END)
+	labelTitle.SetHAlign(gtk.ALIGN_START)
+	labelTitle.SetXAlign(0)
+	box.PackStart(labelTitle, true, true, 0)
+
+	row, _ := gtk.ListBoxRowNew()
+	row.Add(box)
+	return row
+}
+
+// Function that moves the selected row up or down
+func (picker *windowPicker) moveSelection(offset int) {
+	if len(picker.windows) == 0 {
+		return
+	}
+	index := 0
+	if row := picker.listBox.GetSelectedRow(); row != nil {
+		index = max(0, min(len(picker.windows)-1, row.GetIndex()+offset))
+	}
+	if row := picker.listBox.GetRowAtIndex(index); row != nil {
+		picker.listBox.SelectRow(row)
+		// Focusing the row scrolls the list to it, then the focus goes back to the entry to keep typing
+		row.GrabFocus()
+		picker.searchEntry.GrabFocusWithoutSelecting()
+	}
+}
+
+// Function that activates the window of the selected row and closes the popup
+func (picker *windowPicker) activateSelected() {
+	row := picker.listBox.GetSelectedRow()
+	if row == nil || row.GetIndex() >= len(picker.windows) {
+		return
+	}
+	selectedWindow := picker.windows[row.GetIndex()]
+	picker.close()
+	go func() {
+		fmt.Println("(Callback) Window picked:", selectedWindow.windowToString())
+		if err := activateWindow(getXWindow(selectedWindow.id)); err != nil {
+			fmt.Println("ERROR ACTIVATING WINDOW: ", err)
+		}
+	}()
+}
+
+// Function that closes the popup
+func (picker *windowPicker) close() {
+	if picker.window == nil {
+		return
+	}
+	window := picker.window
+	picker.window = nil
+	if openPicker == picker {
+		openPicker = nil
+	}
+	window.Destroy()
+}
+
+/*
+Function that matches a query against the class, title and desktop name of a window.
+
+Returns:
+  - The score of the best match, the higher the better
+  - Whether the query matches any of them
+*/
+func fuzzyMatchWindow(query string, window window) (int, bool) {
+	if len(strings.TrimSpace(query)) == 0 {
+		return 0, true
+	}
+	bestScore, matched := 0, false
+	for _, text := range []string{window.title, window.getClass(), window.desktopName} {
+		if score, ok := fuzzyScore(query, text); ok && (!matched || score > bestScore) {
+			bestScore, matched = score, true
+		}
+	}
+	return bestScore, matched
+}
+
+/*
+Function that matches a query against a text, every character of the query (spaces are ignored) must appear in the
+text in the same order. Consecutive characters and characters at the beginning of a word score higher, gaps between
+them score lower.
+
+Returns:
+  - The score of the match
+  - Whether the query matches the text
+*/
+func fuzzyScore(query string, text string) (int, bool) {
+	queryRunes := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	textRunes := []rune(strings.ToLower(text))
+	score, queryIndex, lastMatch := 0, 0, -1
+	for textIndex, character := range textRunes {
+		if queryIndex == len(queryRunes) {
+			break
+		}
+		if character != queryRunes[queryIndex] {
+			continue
+		}
+		switch {
+		case lastMatch >= 0 && textIndex == lastMatch+1:
+			score += 15 // Consecutive characters
+		case textIndex == 0 || !unicode.IsLetter(textRunes[textIndex-1]) && !unicode.IsDigit(textRunes[textIndex-1]):
+			score += 10 // Beginning of a word
+		default:
+			score += 1
+		}
+		if lastMatch >= 0 {
+			score -= min(textIndex-lastMatch-1, 5) // Gap between characters
+		}
+		lastMatch = textIndex
+		queryIndex++
+	}
+	if queryIndex < len(queryRunes) {
+		return 0, false
+	}
+	return score, true
+}
@@ -0,0 +1,377 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Section and options from config file related with the layout snapshots
+const (
+	sectionSnapshots      = "snapshots"
+	optionSnapshotNames   = "names" // Keys of the saved snapshots
+	optionLastSnapshot    = "last"  // Key of the last snapshot saved or restored, the one restored by the hotkey
+	prefixOptionSnapshots = "snapshot_"
+)
+
+/*
+Layout of a window saved in a snapshot, it's saved in the config file with encodeConfigFields.
+
+The geometry is relative to the monitor when the monitor is known, so the layout can be restored after the monitors
+are rearranged.
+*/
+type windowLayout struct {
+	id       string
+	class    string
+	title    string
+	desktop  int
+	geometry string
+	states   []string
+	monitor  string // Name of the monitor the window was on, empty if the geometry is absolute
+}
+
+// Function that returns the layout of a window as a string to be saved in the config file
+func (layout windowLayout) encode() string {
+	return encodeConfigFields([]string{
+		layout.id,
+		layout.class,
+		layout.title,
+		strconv.Itoa(layout.desktop),
+		layout.geometry,
+		strings.Join(layout.states, "|"),
+		layout.monitor,
+	})
+}
+
+// Function that parses the layout of a window saved in the config file
+func decodeWindowLayout(value string) (windowLayout, error) {
+	fields, err := decodeConfigFields(value)
+	if err != nil {
+		return windowLayout{}, err
+	}
+	if len(fields) == 6 {
+		fields = append(fields, "") // Saved before the monitors were taken into account, the geometry is absolute
+	}
+	if len(fields) != 7 {
+		return windowLayout{}, fmt.Errorf("the window layout \"%s\" is not valid", value)
+	}
+	desktop, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return windowLayout{}, err
+	}
+	layout := windowLayout{
+		id:       fields[0],
+		class:    fields[1],
+		title:    fields[2],
+		desktop:  desktop,
+		geometry: fields[4],
+		monitor:  fields[6],
+	}
+	if len(fields[5]) > 0 {
+		layout.states = strings.Split(fields[5], "|")
+	}
+	return layout, nil
+}
+
+// Function that returns the key used in the config file for a snapshot name
+func getSnapshotKey(name string) string {
+	return prefixOptionSnapshots + hex.EncodeToString([]byte(name))
+}
+
+// Function that returns the name of a snapshot from its key used in the config file
+func getSnapshotName(key string) string {
+	name, err := hex.DecodeString(strings.TrimPrefix(key, prefixOptionSnapshots))
+	if err != nil {
+		return key
+	}
+	return string(name)
+}
+
+// Function that reads an option of the section of snapshots from the config file
+func (mainGUI *MainGUI) getConfigSnapshots(option string) string {
+	result, _ := mainGUI.application.Emit(signalGetConfig, glib.TYPE_STRING, sectionSnapshots, option)
+	value, _ := result.(string)
+	return value
+}
+
+// Function that writes an option of the section of snapshots to the config file
+func (mainGUI *MainGUI) setConfigSnapshots(option string, value string) bool {
+	result, _ := mainGUI.application.Emit(signalUpdateConfig, glib.TYPE_BOOLEAN, sectionSnapshots, option, value)
+	saved, _ := result.(bool)
+	return saved
+}
+
+// Function that returns the keys of the saved snapshots
+func (mainGUI *MainGUI) getSnapshotKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(mainGUI.getConfigSnapshots(optionSnapshotNames), ",") {
+		if len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Function that saves the geometry, desktop and state of every window of the current order into a named snapshot
+func (mainGUI *MainGUI) saveSnapshot(name string) bool {
+	var layouts []string
+	var savedIds []string
+	for _, window := range currentOrder {
+		if slices.Contains(savedIds, window.id) || !isWindowOpen(window.id) {
+			continue // Cloned or closed window
+		}
+		savedIds = append(savedIds, window.id)
+		layout := windowLayout{id: window.id, class: window.class, title: window.title, desktop: window.desktop}
+		if geometry, err := xConn.GetWindowGeometry(getXWindow(window.id)); geometry != nil && err == nil {
+			monitor, frame := toMonitorFrame(geometry.Frame)
+			layout.monitor, layout.geometry = monitor, frame.String()
+		}
+		states, _ := xConn.GetWindowStates(getXWindow(window.id))
+		for state, active := range states {
+			if active {
+				layout.states = append(layout.states, string(state))
+			}
+		}
+		slices.Sort(layout.states)
+		layouts = append(layouts, layout.encode())
+	}
+	if len(layouts) == 0 {
+		return false
+	}
+
+	key := getSnapshotKey(name)
+	if !mainGUI.setConfigSnapshots(key, strings.Join(layouts, ",")) {
+		return false
+	}
+	keys := mainGUI.getSnapshotKeys()
+	if !slices.Contains(keys, key) {
+		keys = append(keys, key)
+	}
+	mainGUI.setConfigSnapshots(optionSnapshotNames, strings.Join(keys, ","))
+	mainGUI.setConfigSnapshots(optionLastSnapshot, key)
+	return true
+}
+
+// Function that deletes a snapshot from the config file
+func (mainGUI *MainGUI) deleteSnapshot(key string) {
+	mainGUI.setConfigSnapshots(optionSnapshotNames, strings.Join(removeItem(mainGUI.getSnapshotKeys(), key), ","))
+	mainGUI.setConfigSnapshots(key, "")
+	if mainGUI.getConfigSnapshots(optionLastSnapshot) == key {
+		mainGUI.setConfigSnapshots(optionLastSnapshot, "")
+	}
+}
+
+/*
+Function that restores a snapshot, every saved window is matched with an open window by its id, if the window doesn't
+exist anymore it's matched by its class and title and finally by its class only.
+
+The snapshot is read from the config file in the main loop and the windows are restored in a goroutine.
+*/
+func (mainGUI *MainGUI) restoreSnapshot(key string) {
+	var layouts []windowLayout
+	for _, value := range strings.Split(mainGUI.getConfigSnapshots(key), ",") {
+		if layout, err := decodeWindowLayout(value); err == nil {
+			layouts = append(layouts, layout)
+		}
+	}
+	if len(layouts) == 0 {
+		fmt.Println("ERROR SNAPSHOT NOT FOUND OR EMPTY: ", getSnapshotName(key))
+		return
+	}
+	mainGUI.setConfigSnapshots(optionLastSnapshot, key)
+	go restoreWindowLayouts(layouts)
+}
+
+// Function that matches every saved layout with an open window and restores it
+func restoreWindowLayouts(layouts []windowLayout) {
+	openWindows := listWindows(false)
+	for index := range openWindows {
+		openWindows[index].class = openWindows[index].getClass()
+	}
+	var usedIds []string
+	// Anonymous function that finds the first open window not used yet that satisfies a condition
+	findWindow := func(matches func(openWindow window) bool) *window {
+		for index, openWindow := range openWindows {
+			if !slices.Contains(usedIds, openWindow.id) && matches(openWindow) {
+				usedIds = append(usedIds, openWindow.id)
+				return &openWindows[index]
+			}
+		}
+		return nil
+	}
+
+	var pending []windowLayout
+	matchedWindows := map[int]*window{}
+	for index, layout := range layouts {
+		if openWindow := findWindow(func(openWindow window) bool {
+			return openWindow.id == layout.id && openWindow.class == layout.class
+		}); openWindow != nil {
+			matchedWindows[index] = openWindow
+		}
+	}
+	for index, layout := range layouts {
+		if matchedWindows[index] != nil {
+			continue
+		}
+		if openWindow := findWindow(func(openWindow window) bool {
+			return openWindow.class == layout.class && openWindow.title == layout.title
+		}); openWindow != nil {
+			matchedWindows[index] = openWindow
+		}
+	}
+	for index, layout := range layouts {
+		if matchedWindows[index] != nil {
+			continue
+		}
+		if openWindow := findWindow(func(openWindow window) bool { return openWindow.class == layout.class }); openWindow != nil {
+			matchedWindows[index] = openWindow
+		} else {
+			pending = append(pending, layout)
+		}
+	}
+	for _, layout := range pending {
+		fmt.Printf("(Snapshot) No window found for: {Class: %s, Title: %s}\n", layout.class, layout.title)
+	}
+
+	for index, layout := range layouts {
+		if openWindow := matchedWindows[index]; openWindow != nil {
+			restoreWindowLayout(getXWindow(openWindow.id), layout)
+		}
+	}
+}
+
+// Function that applies a saved layout to a window: desktop, states and geometry
+func restoreWindowLayout(window xlib.Window, layout windowLayout) {
+	currentStates, err := xConn.GetWindowStates(window)
+	if err != nil {
+		fmt.Println("ERROR RESTORING WINDOW LAYOUT: ", err)
+		return
+	}
+	if layout.desktop >= 0 {
+		_, _ = xConn.MoveWindowToDesktop(window, layout.desktop)
+	}
+	// States are removed before moving the window, a maximized or fullscreen window can't be moved
+	for _, state := range xlib.WindowStates {
+		if currentStates[state] && !slices.Contains(layout.states, string(state)) {
+			_, _ = xConn.ChangeWindowState(window, xlib.StateRemove, state)
+		}
+	}
+	if frame, err := xlib.ParseRectangle(layout.geometry); err == nil {
+		if _, err = xConn.MoveResizeFrame(window, fromMonitorFrame(layout.monitor, frame)); err != nil {
+			fmt.Println("ERROR RESTORING WINDOW GEOMETRY: ", err)
+		}
+	}
+	for _, state := range xlib.WindowStates {
+		if !currentStates[state] && slices.Contains(layout.states, string(state)) {
+			_, _ = xConn.ChangeWindowState(window, xlib.StateAdd, state)
+		}
+	}
+	if slices.Contains(layout.states, string(xlib.StateHidden)) {
+		_, _ = xConn.IconifyWindow(window)
+	} else if currentStates[xlib.StateHidden] {
+		_, _ = xConn.ActivateWindow(window)
+	}
+}
+
+// Function that restores the last snapshot saved or restored. Callback of global hotkey
+func (mainGUI *MainGUI) restoreLastSnapshot() {
+	glib.IdleAdd(func() {
+		if key := mainGUI.getConfigSnapshots(optionLastSnapshot); len(key) > 0 {
+			mainGUI.restoreSnapshot(key)
+		}
+	})
+}
+
+// Function that creates the menu of the button of layout snapshots, it gets created again every time it's shown
+func (contentTabVentanas *contentTabVentanas) setupMenuSnapshots() {
+	mainGUI := &contentTabVentanas.mainGUI
+
+	obj, _ := mainGUI.builder.GetObject("buttonSnapshots")
+	buttonSnapshots := obj.(*gtk.MenuButton)
+
+	// Anonymous function that fills the menu with the saved snapshots
+	funcCreateMenu := func() {
+		menu, _ := gtk.MenuNew()
+
+		saveItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_snapshots_save"))
+		saveItem.Connect("activate", func(item *gtk.MenuItem) { contentTabVentanas.showDialogSaveSnapshot() })
+		saveItem.SetSensitive(len(currentOrder) > 0)
+		menu.Add(saveItem)
+
+		separator, _ := gtk.SeparatorMenuItemNew()
+		menu.Add(separator)
+
+		keys := mainGUI.getSnapshotKeys()
+		if len(keys) == 0 {
+			emptyItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_snapshots_empty"))
+			emptyItem.SetSensitive(false)
+			menu.Add(emptyItem)
+		}
+		for _, key := range keys {
+			snapshotItem, _ := gtk.MenuItemNewWithLabel(getSnapshotName(key))
+			subMenu, _ := gtk.MenuNew()
+
+			restoreItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_snapshots_restore"))
+			restoreItem.Connect("activate", func(item *gtk.MenuItem) { mainGUI.restoreSnapshot(key) })
+			subMenu.Add(restoreItem)
+
+			deleteItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("delete"))
+			deleteItem.Connect("activate", func(item *gtk.MenuItem) { mainGUI.deleteSnapshot(key) })
+			subMenu.Add(deleteItem)
+
+			snapshotItem.SetSubmenu(subMenu)
+			menu.Add(snapshotItem)
+		}
+		menu.ShowAll()
+		buttonSnapshots.SetPopup(menu)
+	}
+	funcCreateMenu()
+	buttonSnapshots.Connect("toggled", func(button *gtk.MenuButton) {
+		if button.GetActive() {
+			funcCreateMenu()
+		}
+	})
+}
+
+// Function that shows a *gtk.Dialog asking for the name of a new snapshot
+func (contentTabVentanas *contentTabVentanas) showDialogSaveSnapshot() {
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(fmt.Sprintf("%s - %s", title, funcGetStringResource("gui_snapshots_name_title")))
+	dialog.SetIcon(defaultAppIcon)
+	dialog.SetTransientFor(contentTabVentanas.mainGUI.window)
+	dialog.SetModal(true)
+	_, _ = dialog.AddButton(funcGetStringResource("cancel"), gtk.RESPONSE_CANCEL)
+	_, _ = dialog.AddButton(funcGetStringResource("accept"), gtk.RESPONSE_ACCEPT)
+	dialog.SetDefaultResponse(gtk.RESPONSE_ACCEPT)
+
+	contentArea, _ := dialog.GetContentArea()
+	contentArea.SetSpacing(10)
+	contentArea.SetMarginStart(10)
+	contentArea.SetMarginEnd(10)
+	contentArea.SetMarginTop(10)
+	label, _ := gtk.LabelNew(funcGetStringResource("gui_snapshots_name_label"))
+	label.SetLineWrap(true)
+	contentArea.Add(label)
+	entry, _ := gtk.EntryNew()
+	entry.SetActivatesDefault(true)
+	contentArea.Add(entry)
+
+	dialog.Connect("response", func(dialog *gtk.Dialog, response int) {
+		name, _ := entry.GetText()
+		name = strings.TrimSpace(name)
+		if response == int(gtk.RESPONSE_ACCEPT) && len(name) > 0 {
+			if !contentTabVentanas.mainGUI.saveSnapshot(name) {
+				fmt.Println("ERROR SAVING SNAPSHOT: ", name)
+			}
+		}
+		dialog.Destroy()
+	})
+	dialog.ShowAll()
+}
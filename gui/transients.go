@@ -0,0 +1,71 @@
+package gui
+
+import (
+	"slices"
+	"strconv"
+
+	"linux-windows-switcher/libs/xlib"
+)
+
+// Maximum number of windows followed through "WM_TRANSIENT_FOR", it protects from loops between badly behaved clients
+const maxTransientDepth = 8
+
+/*
+Function that returns the chain of windows a window is transient for: the window it's transient for (e.g. the main
+window of a dialog), the one that window is transient for and so on. Only the open client windows are followed, a
+window transient for the root window or for a hidden group leader is not transient for any window.
+
+Returns:
+  - The windows the window is transient for, from the nearest to the farthest. Empty if it isn't transient for any
+*/
+func getTransientChain(window xlib.Window) []xlib.Window {
+	var chain []xlib.Window
+	for current := window; len(chain) < maxTransientDepth; {
+		transientFor, err := xConn.GetTransientFor(current)
+		if err != nil || transientFor == xlib.Window(0) || transientFor == xConn.GetRootWindow() ||
+			transientFor == window || slices.Contains(chain, transientFor) ||
+			!isWindowOpen(strconv.FormatUint(uint64(transientFor), 10)) {
+			break
+		}
+		chain = append(chain, transientFor)
+		current = transientFor
+	}
+	return chain
+}
+
+// Function that returns the id of the window that groups a window in the rotation: the last window of its chain of
+// transient windows (see getTransientChain), the window itself if it isn't transient for any window
+func getTransientRootId(windowId string) string {
+	if chain := getTransientChain(getXWindow(windowId)); len(chain) > 0 {
+		return strconv.FormatUint(uint64(chain[len(chain)-1]), 10)
+	}
+	return windowId
+}
+
+/*
+Function that returns the topmost open transient window (dialog) of a window following "_NET_CLIENT_LIST_STACKING",
+the transients of its transients included. The transient windows whose type is skipped (see skippedWindowTypes) are
+not taken into account, e.g. the palettes of an image editor.
+
+Returns:
+  - The topmost transient window, 0 if the window has none
+*/
+func getTopmostTransient(window xlib.Window) xlib.Window {
+	clientListStacking, err := xConn.GetWindowProperty(xConn.GetRootWindow(), "_NET_CLIENT_LIST_STACKING")
+	if err != nil || clientListStacking == nil {
+		return xlib.Window(0)
+	}
+	stacking := clientListStacking.GetLong()
+	// The stacking order goes from bottom to top
+	for index := len(stacking) - 1; index >= 0; index-- {
+		candidate := xlib.Window(stacking[index])
+		if candidate == window || !slices.Contains(getTransientChain(candidate), window) {
+			continue
+		}
+		if windowType, err := xConn.GetWindowType(candidate); err == nil &&
+			!slices.Contains(skippedWindowTypes, windowType) {
+			return candidate
+		}
+	}
+	return xlib.Window(0)
+}
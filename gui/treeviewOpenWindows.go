@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"linux-windows-switcher/libs/xlib"
+
 	"github.com/gotk3/gotk3/gdk"
 
 	"github.com/gotk3/gotk3/glib"
@@ -17,7 +19,7 @@ import (
 
 type listaVentanas struct {
 	contentTabVentanas         contentTabVentanas
-	listStoreActiveWindows     *gtk.ListStore
+	treeStoreActiveWindows     *gtk.TreeStore
 	treeViewActiveWindows      *gtk.TreeView
 	treeSelectionActiveWindows *gtk.TreeSelection
 	signalHandlerRowDeleted    glib.SignalHandle
@@ -26,7 +28,7 @@ type listaVentanas struct {
 }
 
 const (
-	// Columns associated to the *gtk.ListModel
+	// Columns associated to the *gtk.TreeStore
 	columnOrder = iota
 	columnId
 	columnDesktopNumber
@@ -40,6 +42,13 @@ const (
 	columnFontWeight
 	columnDeletedWindow
 	columnIcon
+	columnGeometry
+	columnDwell
+	columnSlot
+	columnType
+	columnTypeName
+	columnTransientFor // Id of the window of the parent row, empty for the rows of the first level
+	columnTopLevel     // Whether the row is on the first level, the rows of transient windows (dialogs) are not
 
 	// Default values to columns from model
 	valuecolumnPadding            = 6
@@ -86,12 +95,24 @@ func (listaVentanas *listaVentanas) initLocale() {
 	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("columnDesktopName")
 	columnDesktopName_ := obj.(*gtk.TreeViewColumn)
 	columnDesktopName_.SetTitle(funcGetStringResource("gui_treeview_column_desktop_name"))
+
+	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("columnGeometry")
+	columnGeometry_ := obj.(*gtk.TreeViewColumn)
+	columnGeometry_.SetTitle(funcGetStringResource("gui_treeview_column_geometry"))
+
+	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("columnType")
+	columnType_ := obj.(*gtk.TreeViewColumn)
+	columnType_.SetTitle(funcGetStringResource("gui_treeview_column_type"))
+
+	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("columnDwell")
+	columnDwell_ := obj.(*gtk.TreeViewColumn)
+	columnDwell_.SetTitle(funcGetStringResource("gui_treeview_column_dwell"))
 }
 
 // Config function
 func (listaVentanas *listaVentanas) setupLista() {
 	obj, _ := listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("modelActiveWindows")
-	listaVentanas.listStoreActiveWindows = obj.(*gtk.ListStore)
+	listaVentanas.treeStoreActiveWindows = obj.(*gtk.TreeStore)
 
 	returnItemToInitialPos := false // wether an item should be returned to its initial pos
 	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("treeSelectionActiveWindows")
@@ -115,17 +136,20 @@ func (listaVentanas *listaVentanas) setupLista() {
 				goValue, _ = value.GoValue()
 				deleted := goValue.(bool)
 
-				listaVentanas.treeViewActiveWindows.SetReorderable(!excluded && !deleted)
-				returnItemToInitialPos = excluded || deleted
+				// The rows of transient windows stay under their parent row
+				topLevel := listaVentanas.isTopLevelRow(iter)
+
+				listaVentanas.treeViewActiveWindows.SetReorderable(!excluded && !deleted && topLevel)
+				returnItemToInitialPos = excluded || deleted || !topLevel
 			}
 		},
 	)
 
 	// Handler of signal "row-deleted". This signal is emitted when a row has been deleted (drag-n-drop)
 	// This signal is emitted after the signal "row-inserted" is emitted when drag-n-drop
-	listaVentanas.signalHandlerRowDeleted = listaVentanas.listStoreActiveWindows.Connect(
+	listaVentanas.signalHandlerRowDeleted = listaVentanas.treeStoreActiveWindows.Connect(
 		"row-deleted",
-		func(store *gtk.ListStore, path *gtk.TreePath) {
+		func(store *gtk.TreeStore, path *gtk.TreePath) {
 			if currentlySelectedRow != -1 && returnItemToInitialPos {
 				var iterItemToReturn *gtk.TreeIter
 				store.ForEach(func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
@@ -144,8 +168,11 @@ func (listaVentanas *listaVentanas) setupLista() {
 						listaVentanas.treeSelectionActiveWindows.SelectIter(iterPositionToReturn)
 						glib.IdleAdd(func() {
 							time.Sleep(time.Second / 2)
-							store.MoveBefore(iterItemToReturn, iterPositionToReturn)
+							listaVentanas.moveRowBefore(iterItemToReturn, iterPositionToReturn)
 						})
+					} else if !listaVentanas.isTopLevelRow(iterItemToReturn) {
+						// The row was the last one of the first level and it was dropped into another row
+						glib.IdleAdd(func() { listaVentanas.moveRowBefore(iterItemToReturn, nil) })
 					}
 				}
 			}
@@ -160,9 +187,22 @@ func (listaVentanas *listaVentanas) setupLista() {
 		},
 	)
 	// Handler of signal "row-inserted". This signal is emitted when a row has been inserted (drag-n-drop)
-	listaVentanas.signalHandlerRowInserted = listaVentanas.listStoreActiveWindows.Connect(
+	listaVentanas.signalHandlerRowInserted = listaVentanas.treeStoreActiveWindows.Connect(
 		"row-inserted",
-		func(store *gtk.ListStore, path *gtk.TreePath, iter *gtk.TreeIter) {
+		func(store *gtk.TreeStore, path *gtk.TreePath, iter *gtk.TreeIter) {
+			if path.GetDepth() > 1 {
+				// The rows of transient windows of the dragged row are copied along with it, any other row dropped
+				// into a row is returned to its initial position
+				var parentIter gtk.TreeIter
+				if store.IterParent(&parentIter, iter) {
+					value, _ := store.GetValue(&parentIter, columnOrder)
+					goValue, _ := value.GoValue()
+					if goValue.(int) != currentlySelectedRow {
+						returnItemToInitialPos = true
+					}
+				}
+				return
+			}
 			returnItemToInitialPos = false         // Wether item should be returned to its initial position
 			var iterFirstExcludedRow *gtk.TreeIter // *gtk.Iter of first excluded row if any
 			store.ForEach(func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
@@ -213,26 +253,26 @@ func (listaVentanas *listaVentanas) setupLista() {
 					column.GetTitle() != stringColumnTitle {
 					return false
 				}
-				iter, _ := listaVentanas.listStoreActiveWindows.GetIter(path)
+				iter, _ := listaVentanas.treeStoreActiveWindows.GetIter(path)
 
 				// *gtk.CellRendererText where to show the tooltip
 				var obj glib.IObject
 				toolTipText := ""
 				if column.GetTitle() == stringColumnDesktop {
 					obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("DesktopName")
-					value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnDesktopName)
+					value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnDesktopName)
 					goValue, _ := value.GoValue()
 					desktopName := goValue.(string)
 					toolTipText = desktopName
 				} else if column.GetTitle() == stringColumnClass {
 					obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("Class")
-					value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnClass)
+					value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnClass)
 					goValue, _ := value.GoValue()
 					class := goValue.(string)
 					toolTipText = strings.TrimPrefix(class, prefixClonedWindow)
 				} else if column.GetTitle() == stringColumnTitle {
 					obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("Title")
-					value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnTitle)
+					value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnTitle)
 					goValue, _ := value.GoValue()
 					title := goValue.(string)
 					toolTipText = title
@@ -274,7 +314,7 @@ func (listaVentanas *listaVentanas) setupLista() {
 	// Handler of signal "drag-end". This signal is emitted when drag ends on the *gtk.TreeView
 	listaVentanas.treeViewActiveWindows.Connect("drag-end", func(view *gtk.TreeView, ctx *gdk.DragContext) {
 		var iterRowDesiredToSelect *gtk.TreeIter
-		listaVentanas.listStoreActiveWindows.ForEach(
+		listaVentanas.treeStoreActiveWindows.ForEach(
 			func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 				value, _ := model.GetValue(iter, columnOrder)
 				if valInt, err := value.GoValue(); err == nil {
@@ -288,7 +328,7 @@ func (listaVentanas *listaVentanas) setupLista() {
 		)
 		listaVentanas.treeSelectionActiveWindows.HandlerUnblock(signalChangedSelection)
 		if iterRowDesiredToSelect != nil {
-			if path, err := listaVentanas.listStoreActiveWindows.GetPath(iterRowDesiredToSelect); err == nil {
+			if path, err := listaVentanas.treeStoreActiveWindows.GetPath(iterRowDesiredToSelect); err == nil {
 				view.SetCursor(path, nil, false)
 				listaVentanas.treeSelectionActiveWindows.SelectIter(iterRowDesiredToSelect)
 			}
@@ -311,20 +351,24 @@ func (listaVentanas *listaVentanas) setupLista() {
 	cellRendererToglleColumnExcluded.Connect(
 		"toggled",
 		func(toggle *gtk.CellRendererToggle, path string) {
+			// The rows of transient windows (dialogs) are not in the order, they can't be excluded
+			if strings.Contains(path, ":") {
+				return
+			}
 			// Make *gtk.TreeView unorderable and block signal when selection changes
 			listaVentanas.treeViewActiveWindows.SetReorderable(false)
 			listaVentanas.treeSelectionActiveWindows.HandlerBlock(signalChangedSelection)
 
 			// *gtk.Iter (row) that is going to be excluded
-			iter, _ := listaVentanas.listStoreActiveWindows.GetIterFromString(path)
+			iter, _ := listaVentanas.treeStoreActiveWindows.GetIterFromString(path)
 
 			// Current value of column "Excluded" on desired row (iter)
-			value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnExcluded)
+			value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnExcluded)
 			goValue, _ := value.GoValue()
 			excluded := goValue.(bool)
 
 			// Current value of column "Deleted" on desired row (iter)
-			value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnDeletedWindow)
+			value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnDeletedWindow)
 			goValue, _ = value.GoValue()
 			deleted := goValue.(bool)
 
@@ -333,17 +377,17 @@ func (listaVentanas *listaVentanas) setupLista() {
 			if handleToggle {
 				newVal := !excluded
 				excluded = newVal
-				_ = listaVentanas.listStoreActiveWindows.SetValue(iter, columnExcluded, newVal) // Set new value
+				_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnExcluded, newVal) // Set new value
 
 				glib.IdleAdd(func() {
 					updateDefaultOrder := false
 					if excludeWindow { // Window is not valid
-						_ = listaVentanas.listStoreActiveWindows.SetValue(iter, columnDeletedWindow, true)
+						_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnDeletedWindow, true)
 
-						value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnClass)
+						value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnClass)
 						goValue, _ := value.GoValue()
 						class := goValue.(string)
-						_ = listaVentanas.listStoreActiveWindows.SetValue(iter, columnClass, prefixClosedWindow+class)
+						_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnClass, prefixClosedWindow+class)
 
 						updateDefaultOrder = true
 						excludeWindow = false
@@ -352,11 +396,11 @@ func (listaVentanas *listaVentanas) setupLista() {
 					}
 					if newVal {
 						// Move iter to end of the *gtk.TreeView
-						listaVentanas.listStoreActiveWindows.MoveBefore(iter, nil)
+						listaVentanas.treeStoreActiveWindows.MoveBefore(iter, nil)
 					} else {
 						// Search for the first excluded *gtk.Iter (window)
 						var iterFirstExcludedRow *gtk.TreeIter
-						listaVentanas.listStoreActiveWindows.ForEach(func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+						listaVentanas.treeStoreActiveWindows.ForEach(func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 							// Value column excluded
 							value, _ = model.GetValue(iter, columnExcluded)
 							goValue, _ = value.GoValue()
@@ -370,7 +414,7 @@ func (listaVentanas *listaVentanas) setupLista() {
 						})
 						// Move iter below the first excluded item if exists, otherwise move to the end of *gtk.TreeView
 						if iterFirstExcludedRow != nil {
-							listaVentanas.listStoreActiveWindows.MoveBefore(iter, iterFirstExcludedRow)
+							listaVentanas.treeStoreActiveWindows.MoveBefore(iter, iterFirstExcludedRow)
 						}
 					}
 					// Emit signal to stablish order, a window was excluded/included
@@ -402,6 +446,61 @@ func (listaVentanas *listaVentanas) setupLista() {
 		return false
 	})
 
+	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("cellRenderGeometry")
+	cellRendererTextColumnGeometry := obj.(*gtk.CellRendererText)
+	// Handler of signal "edited". This signal is emitted when a new geometry (WIDTHxHEIGHT+X+Y) is typed on a row, the
+	// window gets moved and resized to it
+	cellRendererTextColumnGeometry.Connect(
+		"edited",
+		func(renderer *gtk.CellRendererText, path string, newGeometry string) {
+			iter, _ := listaVentanas.treeStoreActiveWindows.GetIterFromString(path)
+			value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnId)
+			windowId, _ := value.GetString()
+			frame, err := xlib.ParseRectangle(strings.TrimSpace(newGeometry))
+			if err != nil {
+				fmt.Println("ERROR INVALID GEOMETRY: ", err)
+				return
+			}
+			if _, err = xConn.MoveResizeFrame(getXWindow(windowId), frame); err != nil {
+				fmt.Println("ERROR MOVING WINDOW: ", err)
+				return
+			}
+			go func() {
+				// Give some time to the window manager to place the window before reading its geometry again
+				time.Sleep(time.Second / 4)
+				geometry := getWindowGeometry(getXWindow(windowId))
+				glib.IdleAdd(func() { listaVentanas.updateWindowGeometry(windowId, geometry) })
+			}()
+		},
+	)
+
+	obj, _ = listaVentanas.contentTabVentanas.mainGUI.builder.GetObject("cellRenderDwell")
+	cellRendererSpinColumnDwell := obj.(*gtk.CellRendererSpin)
+	// Handler of signal "edited". This signal is emitted when a new dwell time is set on a row, it's the time the window
+	// stays active during the auto-rotation
+	cellRendererSpinColumnDwell.Connect(
+		"edited",
+		func(renderer *gtk.CellRendererSpin, path string, newDwell string) {
+			dwell, err := strconv.Atoi(strings.TrimSpace(newDwell))
+			if err != nil || dwell <= 0 {
+				fmt.Println("ERROR INVALID DWELL TIME: ", newDwell)
+				return
+			}
+			iter, _ := listaVentanas.treeStoreActiveWindows.GetIterFromString(path)
+			window := listaVentanas.getWindowFromRowIter(iter)
+			listaVentanas.contentTabVentanas.mainGUI.setWindowDwell(window, dwell)
+			// The windows of the same class share the dwell time, cloned windows included
+			listaVentanas.treeStoreActiveWindows.ForEach(
+				func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+					if getOptionDwell(listaVentanas.getWindowFromRowIter(iter)) == getOptionDwell(window) {
+						_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnDwell, dwell)
+					}
+					return false // loop through all rows in the treeview
+				},
+			)
+		},
+	)
+
 	takeOffSelection := false // Wether the selection of the *gtk.TreeView should be removed
 	showContextMenu := false  // Wether the contextual menu should be shown
 
@@ -409,7 +508,7 @@ func (listaVentanas *listaVentanas) setupLista() {
 	functakeOffSelection := func() {
 		currentlySelectedRow = -1
 		amountOfItems := 0
-		listaVentanas.listStoreActiveWindows.ForEach(
+		listaVentanas.treeStoreActiveWindows.ForEach(
 			func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 				val, _ := model.ToTreeModel().GetValue(iter, columnOrder)
 				if valInt, err := val.GoValue(); err == nil {
@@ -453,20 +552,20 @@ func (listaVentanas *listaVentanas) setupLista() {
 				disableContextMenu := true
 				if eventButton := gdk.EventButtonNewFromEvent(event); eventButton.Button() == gdk.BUTTON_SECONDARY {
 					if path, _, _, _, exists := view.GetPathAtPos(int(math.Round(eventButton.X())), int(math.Round(eventButton.Y()))); exists {
-						iter, _ := listaVentanas.listStoreActiveWindows.GetIter(path)
+						iter, _ := listaVentanas.treeStoreActiveWindows.GetIter(path)
 
 						// Value of column excluded
-						value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnExcluded)
+						value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnExcluded)
 						goValue, _ := value.GoValue()
 						excluded := goValue.(bool)
 
 						// Value of column cloned
-						value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnCloned)
+						value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnCloned)
 						goValue, _ = value.GoValue()
 						cloned := goValue.(bool)
 
 						// Value of column deleted
-						value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnDeletedWindow)
+						value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnDeletedWindow)
 						goValue, _ = value.GoValue()
 						deleted := goValue.(bool)
 
@@ -519,8 +618,13 @@ func (listaVentanas *listaVentanas) setupLista() {
 			var windowsCurrentOrder []window
 			var windowsDefaultOrder []window
 			if useGUI { // Set order from the UI
-				listaVentanas.listStoreActiveWindows.ForEach(
+				listaVentanas.treeStoreActiveWindows.ForEach(
 					func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+						// The rows of transient windows are not in the order
+						if path.GetDepth() > 1 {
+							return false
+						}
+
 						// Value of column excluded
 						value, _ := model.GetValue(iter, columnExcluded)
 						goValue, _ := value.GoValue()
@@ -566,6 +670,7 @@ func (listaVentanas *listaVentanas) setupLista() {
 			for _, window := range defaultOrder {
 				defaultOrderText = append(defaultOrderText, strconv.Itoa(window.order))
 			}
+			listaVentanas.updateSlots()
 			textToSetCurrentOrder := strings.Join(currentOrderText, ", ")
 			textToSetDefaultOrder := strings.Join(defaultOrderText, ", ")
 			if len(currentOrderText) == 0 {
@@ -597,9 +702,9 @@ func (listaVentanas *listaVentanas) setupLista() {
 
 // Function that deletes all the rows from the *gtk.TreeView
 func (listaVentanas *listaVentanas) clear() {
-	listaVentanas.listStoreActiveWindows.HandlerBlock(listaVentanas.signalHandlerRowDeleted)
-	listaVentanas.listStoreActiveWindows.Clear()
-	listaVentanas.listStoreActiveWindows.HandlerUnblock(listaVentanas.signalHandlerRowDeleted)
+	listaVentanas.treeStoreActiveWindows.HandlerBlock(listaVentanas.signalHandlerRowDeleted)
+	listaVentanas.treeStoreActiveWindows.Clear()
+	listaVentanas.treeStoreActiveWindows.HandlerUnblock(listaVentanas.signalHandlerRowDeleted)
 }
 
 // Function that pop-ups a context menu on the *gtk.TreeView at a specific row cell
@@ -624,24 +729,88 @@ func (listaVentanas *listaVentanas) createContextMenuTreeview(iter *gtk.TreeIter
 		func(item *gtk.MenuItem) { listaVentanas.showDialogChangeWindowTitle(iter) },
 	)
 
-	if clone {
+	switch {
+	case !listaVentanas.isTopLevelRow(iter):
+		// The rows of transient windows go along with their parent row, they can't be cloned nor deleted
+	case clone:
 		menu.Add(cloneItem)
-	} else {
+	default:
 		menu.Add(deleteItem)
 	}
 	menu.Add(changeWindowTitleItem)
 
+	value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnId)
+	windowId, _ := value.GetString()
+	if isWindowOpen(windowId) {
+		menu.Add(listaVentanas.createMenuItemWindowState(windowId))
+
+		closeWindowItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_treeview_context_menu_close_window"))
+		closeWindowItem.Connect("activate", func(item *gtk.MenuItem) {
+			listaVentanas.contentTabVentanas.mainGUI.closeWindows([]window{listaVentanas.getWindowFromRowIter(iter)})
+		})
+		menu.Add(closeWindowItem)
+	}
+
+	separator, _ := gtk.SeparatorMenuItemNew()
+	menu.Add(separator)
+
+	closeExcludedItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_treeview_context_menu_close_excluded"))
+	closeExcludedItem.SetSensitive(len(listaVentanas.getExcludedWindows()) > 0)
+	closeExcludedItem.Connect("activate", func(item *gtk.MenuItem) { listaVentanas.closeExcludedWindows() })
+	menu.Add(closeExcludedItem)
+
 	menu.ShowAll()
 	menu.PopupAtPointer(event)
 }
 
+// Function that creates the item of the context menu with a submenu to change the state of a window
+func (listaVentanas *listaVentanas) createMenuItemWindowState(windowId string) *gtk.MenuItem {
+	window := getXWindow(windowId)
+	states, err := xConn.GetWindowStates(window)
+	if err != nil {
+		fmt.Println("ERROR OBTAINING WINDOW STATE: ", err)
+	}
+
+	windowStateItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_treeview_context_menu_window_state"))
+	subMenu, _ := gtk.MenuNew()
+	for _, state := range xlib.WindowStates {
+		stateItem, _ := gtk.CheckMenuItemNewWithLabel(funcGetStringResource("window_state_" + string(state)))
+		stateItem.SetActive(states[state])
+		stateItem.Connect("toggled", func(item *gtk.CheckMenuItem) {
+			action := xlib.StateRemove
+			if item.GetActive() {
+				action = xlib.StateAdd
+			}
+			if _, err := xConn.ChangeWindowState(window, action, state); err != nil {
+				fmt.Println("ERROR CHANGING WINDOW STATE: ", err)
+			}
+		})
+		subMenu.Add(stateItem)
+	}
+
+	separator, _ := gtk.SeparatorMenuItemNew()
+	subMenu.Add(separator)
+
+	minimizeItem, _ := gtk.MenuItemNewWithLabel(funcGetStringResource("gui_treeview_context_menu_minimize"))
+	minimizeItem.SetSensitive(!states[xlib.StateHidden])
+	minimizeItem.Connect("activate", func(item *gtk.MenuItem) {
+		if _, err := xConn.IconifyWindow(window); err != nil {
+			fmt.Println("ERROR MINIMIZING WINDOW: ", err)
+		}
+	})
+	subMenu.Add(minimizeItem)
+
+	windowStateItem.SetSubmenu(subMenu)
+	return windowStateItem
+}
+
 // Function that clones a row (*gtk.TreeIter) from the *gtk.TreeView of opened windows
 // and puts it just below the original one
 func (listaVentanas *listaVentanas) cloneRow(iter *gtk.TreeIter) {
 	window := listaVentanas.getWindowFromRowIter(iter)
 	window.class = prefixClonedWindow + window.class
 	newOrder := 0
-	listaVentanas.listStoreActiveWindows.ForEach(
+	listaVentanas.treeStoreActiveWindows.ForEach(
 		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 			value, _ := model.GetValue(iter, columnOrder)
 			goValue, _ := value.GoValue()
@@ -664,19 +833,19 @@ func (listaVentanas *listaVentanas) cloneRow(iter *gtk.TreeIter) {
 // Function that deletes a row (*gtk.TreeIter) from the *gtk.TreeView of opened windows
 func (listaVentanas *listaVentanas) deleteRow(iter *gtk.TreeIter) {
 	window := listaVentanas.getWindowFromRowIter(iter)
-	listaVentanas.listStoreActiveWindows.ForEach(
+	listaVentanas.treeStoreActiveWindows.ForEach(
 		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 			value, _ := model.GetValue(iter, columnOrder)
 			goValue, _ := value.GoValue()
 			order := goValue.(int)
 
 			if order > window.order {
-				_ = listaVentanas.listStoreActiveWindows.SetValue(iter, columnOrder, order-1)
+				_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnOrder, order-1)
 			}
 			return false
 		},
 	)
-	listaVentanas.listStoreActiveWindows.Remove(iter)
+	listaVentanas.treeStoreActiveWindows.Remove(iter)
 	// Emit signal to stablish order
 	_, _ = listaVentanas.contentTabVentanas.mainGUI.application.Emit(signalSetOrder, glib.TYPE_NONE, true, false, true)
 }
@@ -690,92 +859,211 @@ Parameters:
   - window: Window to add
   - cloned: Wether the new window to add is cloned or not
   - iter: Iter where to put the new (cloned) window
+
+Returns:
+  - The *gtk.TreeIter of the new row
 */
-func (listaVentanas *listaVentanas) addRow(window window, clonedWindow bool, iter *gtk.TreeIter) {
+func (listaVentanas *listaVentanas) addRow(window window, clonedWindow bool, iter *gtk.TreeIter) *gtk.TreeIter {
 	// Block signal "row-inserted"
-	listaVentanas.listStoreActiveWindows.HandlerBlock(listaVentanas.signalHandlerRowInserted)
+	listaVentanas.treeStoreActiveWindows.HandlerBlock(listaVentanas.signalHandlerRowInserted)
 
 	var newIter *gtk.TreeIter
 	if !clonedWindow {
-		newIter = listaVentanas.listStoreActiveWindows.Insert(window.order - 1)
+		newIter = listaVentanas.treeStoreActiveWindows.Insert(nil, window.order-1)
 	} else {
-		newIter = listaVentanas.listStoreActiveWindows.InsertAfter(iter)
+		newIter = listaVentanas.treeStoreActiveWindows.InsertAfter(nil, iter)
 	}
-	_ = listaVentanas.listStoreActiveWindows.Set(
-		newIter,
-		[]int{
-			columnOrder,
-			columnId,
-			columnDesktopNumber,
-			columnDesktopName,
-			columnClass,
-			columnTitle,
-			columnExcluded,
-			columnCloned,
-			columnPadding,
-			columnEllipsize,
-			columnFontWeight,
-			columnDeletedWindow,
-			columnIcon,
-		},
-		[]any{
-			window.order,
-			window.id,
-			window.desktop,
-			window.desktopName,
-			window.class,
-			window.title,
-			false,
-			clonedWindow,
-			valuecolumnPadding,
-			valueColumnPangoEllipsizeMode,
-			valuecolumnFontWeight,
-			false,
-			window.icon,
-		},
-	)
+	listaVentanas.setRow(newIter, window, clonedWindow)
 	// Unblock signal "row-inserted"
-	listaVentanas.listStoreActiveWindows.HandlerUnblock(listaVentanas.signalHandlerRowInserted)
+	listaVentanas.treeStoreActiveWindows.HandlerUnblock(listaVentanas.signalHandlerRowInserted)
+	return newIter
+}
+
+/*
+Function that adds the rows of the transient windows (dialogs) of a window under its row, and the rows of their
+transient windows under them. The transient windows are not in the order, they are activated along with the window.
+
+Parameters:
+  - window: Window of the parent row
+  - iter: Iter of the parent row
+  - openWindows: Open windows, where the transient windows are looked for
+*/
+func (listaVentanas *listaVentanas) addTransientRows(window window, iter *gtk.TreeIter, openWindows []window) {
+	applicationId := listaVentanas.contentTabVentanas.mainGUI.application.GetApplicationID()
+	for _, transientId := range window.transients {
+		for _, transient := range openWindows {
+			if transient.id != transientId || getWindowSkip(transient, applicationId) != skipTransient {
+				continue
+			}
+			transient.class = transient.getClass()
+			listaVentanas.treeStoreActiveWindows.HandlerBlock(listaVentanas.signalHandlerRowInserted)
+			newIter := listaVentanas.treeStoreActiveWindows.Append(iter)
+			listaVentanas.setRow(newIter, transient, false)
+			listaVentanas.treeStoreActiveWindows.HandlerUnblock(listaVentanas.signalHandlerRowInserted)
+			listaVentanas.addTransientRows(transient, newIter, openWindows)
+		}
+	}
+}
+
+// Function that sets the values of all the columns of a row from a window, the rows of transient windows have no order
+func (listaVentanas *listaVentanas) setRow(iter *gtk.TreeIter, window window, clonedWindow bool) {
+	columns := []int{
+		columnOrder,
+		columnId,
+		columnDesktopNumber,
+		columnDesktopName,
+		columnClass,
+		columnTitle,
+		columnExcluded,
+		columnCloned,
+		columnPadding,
+		columnEllipsize,
+		columnFontWeight,
+		columnDeletedWindow,
+		columnIcon,
+		columnGeometry,
+		columnDwell,
+		columnSlot,
+		columnType,
+		columnTypeName,
+		columnTransientFor,
+		columnTopLevel,
+	}
+	values := []any{
+		window.order,
+		window.id,
+		window.desktop,
+		window.desktopName,
+		window.class,
+		window.title,
+		false,
+		clonedWindow,
+		valuecolumnPadding,
+		valueColumnPangoEllipsizeMode,
+		valuecolumnFontWeight,
+		false,
+		window.icon,
+		window.geometry,
+		listaVentanas.contentTabVentanas.mainGUI.getWindowDwell(window),
+		"",
+		string(window.windowType),
+		getWindowTypeName(window.windowType),
+		window.transientFor,
+		len(window.transientFor) == 0,
+	}
+	for index, column := range columns {
+		_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, column, values[index])
+	}
+}
+
+// Function that returns whether a row is on the first level of the *gtk.TreeView, the rows of transient windows are not
+func (listaVentanas *listaVentanas) isTopLevelRow(iter *gtk.TreeIter) bool {
+	path, err := listaVentanas.treeStoreActiveWindows.GetPath(iter)
+	return err == nil && path.GetDepth() == 1
+}
+
+/*
+Function that moves a row before another row of the first level, or to the end of the first level if "position" is
+nil. A *gtk.TreeStore only moves rows inside their level, so a row that was dropped into another row is copied (with
+its children) to the first level and removed, and the order is set again since the row was out of it.
+
+Parameters:
+  - iter: Iter of the row to move
+  - position: Iter of the row of the first level where to put it before, nil to put it at the end
+*/
+func (listaVentanas *listaVentanas) moveRowBefore(iter *gtk.TreeIter, position *gtk.TreeIter) {
+	store := listaVentanas.treeStoreActiveWindows
+	if listaVentanas.isTopLevelRow(iter) {
+		store.MoveBefore(iter, position)
+		return
+	}
+	store.HandlerBlock(listaVentanas.signalHandlerRowInserted)
+	store.HandlerBlock(listaVentanas.signalHandlerRowDeleted)
+	var newIter *gtk.TreeIter
+	if position != nil {
+		newIter = store.InsertBefore(nil, position)
+	} else {
+		newIter = store.Append(nil)
+	}
+	listaVentanas.copyRow(iter, newIter)
+	store.Remove(iter)
+	store.HandlerUnblock(listaVentanas.signalHandlerRowDeleted)
+	store.HandlerUnblock(listaVentanas.signalHandlerRowInserted)
+	listaVentanas.treeViewActiveWindows.ExpandAll()
+	// Emit signal to stablish order
+	_, _ = listaVentanas.contentTabVentanas.mainGUI.application.Emit(signalSetOrder, glib.TYPE_NONE, true, false, true)
+}
+
+// Function that copies the values of all the columns of a row, and its children, to another row
+func (listaVentanas *listaVentanas) copyRow(source *gtk.TreeIter, destination *gtk.TreeIter) {
+	store := listaVentanas.treeStoreActiveWindows
+	for column := columnOrder; column <= columnTopLevel; column++ {
+		value, _ := store.GetValue(source, column)
+		goValue, err := value.GoValue()
+		if icon, isIcon := goValue.(*gdk.Pixbuf); err != nil || goValue == nil || isIcon && icon == nil {
+			continue
+		}
+		_ = store.SetValue(destination, column, goValue)
+	}
+	var child gtk.TreeIter
+	for hasChild := store.IterChildren(source, &child); hasChild; hasChild = store.IterNext(&child) {
+		listaVentanas.copyRow(&child, store.Append(destination))
+	}
 }
 
 // Function that returns a *window instance based on a row (*gtk.Iter) data
 func (listaVentanas *listaVentanas) getWindowFromRowIter(iter *gtk.TreeIter) window {
-	value, _ := listaVentanas.listStoreActiveWindows.GetValue(iter, columnOrder)
+	value, _ := listaVentanas.treeStoreActiveWindows.GetValue(iter, columnOrder)
 	goValue, _ := value.GoValue()
 	order := goValue.(int)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnId)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnId)
 	goValue, _ = value.GoValue()
 	id := goValue.(string)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnDesktopNumber)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnDesktopNumber)
 	goValue, _ = value.GoValue()
 	desktopNumber := goValue.(int)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnDesktopName)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnDesktopName)
 	goValue, _ = value.GoValue()
 	desktopName := goValue.(string)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnClass)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnClass)
 	goValue, _ = value.GoValue()
 	class := goValue.(string)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnTitle)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnTitle)
 	goValue, _ = value.GoValue()
 	title := goValue.(string)
 
-	value, _ = listaVentanas.listStoreActiveWindows.GetValue(iter, columnIcon)
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnIcon)
 	goValue, _ = value.GoValue()
 	icon := goValue.(*gdk.Pixbuf)
 
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnGeometry)
+	goValue, _ = value.GoValue()
+	geometry := goValue.(string)
+
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnType)
+	goValue, _ = value.GoValue()
+	windowType := xlib.WindowType(goValue.(string))
+
+	value, _ = listaVentanas.treeStoreActiveWindows.GetValue(iter, columnTransientFor)
+	goValue, _ = value.GoValue()
+	transientFor := goValue.(string)
+
 	return window{
-		id:          id,
-		class:       class,
-		title:       title,
-		desktop:     desktopNumber,
-		desktopName: desktopName,
-		order:       order,
-		icon:        icon,
+		id:           id,
+		class:        class,
+		title:        title,
+		desktop:      desktopNumber,
+		desktopName:  desktopName,
+		order:        order,
+		icon:         icon,
+		geometry:     geometry,
+		windowType:   windowType,
+		transientFor: transientFor,
 	}
 }
 
@@ -962,14 +1250,19 @@ func (listaVentanas *listaVentanas) changeWindowTitleInTreeView(window_ window,
 	if !changeWindowTitle(window_.id, newTitle) {
 		return false
 	}
+	return listaVentanas.updateWindowTitle(window_.id, newTitle)
+}
+
+// Function that updates the title of a window in the *gtk.TreeView and in the slices of windows
+func (listaVentanas *listaVentanas) updateWindowTitle(windowId string, newTitle string) bool {
 	titleChangedInTreeView := false
-	listaVentanas.listStoreActiveWindows.ForEach(
+	listaVentanas.treeStoreActiveWindows.ForEach(
 		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
 			value, _ := model.GetValue(iter, columnId)
 			goValue, _ := value.GoValue()
 			id := goValue.(string)
-			if id == window_.id {
-				if listaVentanas.listStoreActiveWindows.SetValue(iter, columnTitle, newTitle) == nil {
+			if id == windowId {
+				if listaVentanas.treeStoreActiveWindows.SetValue(iter, columnTitle, newTitle) == nil {
 					titleChangedInTreeView = true
 				} else {
 					return true // stop looping
@@ -984,7 +1277,7 @@ func (listaVentanas *listaVentanas) changeWindowTitleInTreeView(window_ window,
 
 	funcChangeWindowTitleInSliceOfWindows := func(windowsSlice []window) {
 		for i, winn := range windowsSlice {
-			if winn.id == window_.id {
+			if winn.id == windowId {
 				windowsSlice[i].title = newTitle
 			}
 		}
@@ -994,3 +1287,81 @@ func (listaVentanas *listaVentanas) changeWindowTitleInTreeView(window_ window,
 	funcChangeWindowTitleInSliceOfWindows(defaultOrder)
 	return true
 }
+
+// Function that updates the desktop of a window in the *gtk.TreeView and in the slices of windows
+func (listaVentanas *listaVentanas) updateWindowDesktop(windowId string, desktop int, desktopName string) {
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnId)
+			goValue, _ := value.GoValue()
+			id := goValue.(string)
+			if id == windowId {
+				_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnDesktopNumber, desktop)
+				_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnDesktopName, desktopName)
+			}
+			return false // loop through all rows in the treeview
+		},
+	)
+
+	funcChangeWindowDesktopInSliceOfWindows := func(windowsSlice []window) {
+		for i, winn := range windowsSlice {
+			if winn.id == windowId {
+				windowsSlice[i].desktop = desktop
+				windowsSlice[i].desktopName = desktopName
+			}
+		}
+	}
+	funcChangeWindowDesktopInSliceOfWindows(listaVentanas.windowList)
+	funcChangeWindowDesktopInSliceOfWindows(currentOrder)
+	funcChangeWindowDesktopInSliceOfWindows(defaultOrder)
+}
+
+// Function that updates the geometry of a window in the *gtk.TreeView and in the slices of windows
+func (listaVentanas *listaVentanas) updateWindowGeometry(windowId string, geometry string) {
+	if len(geometry) == 0 {
+		return
+	}
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnId)
+			goValue, _ := value.GoValue()
+			id := goValue.(string)
+			if id == windowId {
+				_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnGeometry, geometry)
+			}
+			return false // loop through all rows in the treeview
+		},
+	)
+
+	funcChangeWindowGeometryInSliceOfWindows := func(windowsSlice []window) {
+		for i, winn := range windowsSlice {
+			if winn.id == windowId {
+				windowsSlice[i].geometry = geometry
+			}
+		}
+	}
+	funcChangeWindowGeometryInSliceOfWindows(listaVentanas.windowList)
+	funcChangeWindowGeometryInSliceOfWindows(currentOrder)
+	funcChangeWindowGeometryInSliceOfWindows(defaultOrder)
+}
+
+// Function that shows on every row its position in the current order (slot), the one used by the global hotkeys that
+// go directly to a window. The windows out of the current order have no slot
+func (listaVentanas *listaVentanas) updateSlots() {
+	slots := map[int]int{} // Structure: key: Order, value: Slot
+	for index, window := range currentOrder {
+		slots[window.order] = index + 1
+	}
+	listaVentanas.treeStoreActiveWindows.ForEach(
+		func(model *gtk.TreeModel, path *gtk.TreePath, iter *gtk.TreeIter) bool {
+			value, _ := model.GetValue(iter, columnOrder)
+			goValue, _ := value.GoValue()
+			slotText := ""
+			if slot, exists := slots[goValue.(int)]; exists {
+				slotText = fmt.Sprintf("<small><i>[%d]</i></small>", slot)
+			}
+			_ = listaVentanas.treeStoreActiveWindows.SetValue(iter, columnSlot, slotText)
+			return false // loop through all rows in the treeview
+		},
+	)
+}
@@ -2,6 +2,7 @@ package gui
 
 import (
 	"fmt"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -17,6 +18,7 @@ import (
 var (
 	funcGetResource       func(resource string) []byte // Anonymous function that returns a slice of bytes from a needed resource
 	funcGetStringResource func(id string) string       // Anonymous function that returns a string from the localizer
+	xConn                 *xlib.Conn                   // Connection to the X server coming from main module
 )
 
 // GetTitle Get title of the application
@@ -38,14 +40,55 @@ func getPixBufAtSize(iconName string, width int, height int) *gdk.Pixbuf {
 	return iconScaled
 }
 
-// This function returns the current active windows using Xlib
+// This function returns the current active windows, their class, title and desktop come from the window tracker and
+// the rest of their properties from Xlib
 func listWindows(includeIcons bool) []window {
-	var desktopNames []string
+	windows, tracked := getTrackedWindows()
+	if !tracked {
+		windows = scanClientWindows()
+	}
+
+	// Get desktop information
+	desktopNames := getDesktopNames()
+
+	// Loop to get the rest of data of every window
+	for index := range windows {
+		// Window
+		win := getXWindow(windows[index].id)
+		window := &windows[index]
+		window.desktopName = getDesktopName(desktopNames, window.desktop)
+		window.geometry = getWindowGeometry(win)
+
+		// Window Icon
+		if includeIcons {
+			window.icon = getWindowIcon(win)
+		}
+
+		// Window type and states
+		window.windowType, _ = xConn.GetWindowType(win)
+		states, _ := xConn.GetWindowStates(win)
+		window.skipTaskbar = states[xlib.StateSkipTaskbar]
+		window.skipPager = states[xlib.StateSkipPager]
+		if transientFor, _ := xConn.GetTransientFor(win); transientFor != xlib.Window(0) {
+			window.transientFor = fmt.Sprint(uint64(transientFor))
+		}
+	}
+	return linkTransientWindows(windows)
+}
+
+/*
+Function that reads the client windows from "_NET_CLIENT_LIST" with their class, title and desktop using Xlib, the
+windows without any of them are left out. It's used when the window tracker is not running.
+
+Returns:
+  - The windows, in the order of the client list
+*/
+func scanClientWindows() []window {
 	var windows []window
 	lookForAlternativeProperty := false
 
 	// Client List
-	netClientListResult, err := xlib.GetWindowProperty(xlib.GetRootWindow(), "_NET_CLIENT_LIST")
+	netClientListResult, err := xConn.GetWindowProperty(xConn.GetRootWindow(), "_NET_CLIENT_LIST")
 	if err != nil {
 		lookForAlternativeProperty = true
 	} else if netClientListResult != nil && len(netClientListResult.GetLong()) == 0 {
@@ -53,7 +96,7 @@ func listWindows(includeIcons bool) []window {
 	}
 	if lookForAlternativeProperty {
 		// GNOME Spec property "_WIN_CLIENT_LIST"
-		netClientListResult, err = xlib.GetWindowProperty(xlib.GetRootWindow(), "_WIN_CLIENT_LIST")
+		netClientListResult, err = xConn.GetWindowProperty(xConn.GetRootWindow(), "_WIN_CLIENT_LIST")
 		if err != nil {
 			return windows
 		} else if netClientListResult != nil && len(netClientListResult.GetLong()) == 0 {
@@ -61,14 +104,6 @@ func listWindows(includeIcons bool) []window {
 		}
 	}
 
-	// Get desktop information
-	netDesktopNamesPropertyResult, err := xlib.GetWindowProperty(xlib.GetRootWindow(), "_NET_DESKTOP_NAMES")
-	if netDesktopNamesPropertyResult != nil && netDesktopNamesPropertyResult.NumberOfItems > 0 {
-		for _, desktopName := range netDesktopNamesPropertyResult.GetString() {
-			desktopNames = append(desktopNames, desktopName)
-		}
-	}
-
 	// Loop to get data of every window
 	for _, windowId := range netClientListResult.GetLong() {
 		// Window
@@ -76,7 +111,7 @@ func listWindows(includeIcons bool) []window {
 
 		// Window Desktop
 		lookForAlternativeProperty = false
-		desktop_, err := xlib.GetWindowProperty(win, "_NET_WM_DESKTOP")
+		desktop_, err := xConn.GetWindowProperty(win, "_NET_WM_DESKTOP")
 		if err != nil {
 			lookForAlternativeProperty = true
 		} else if desktop_ != nil && len(desktop_.GetLong()) == 0 {
@@ -84,7 +119,7 @@ func listWindows(includeIcons bool) []window {
 		}
 		if lookForAlternativeProperty {
 			// GNOME Spec property "_WIN_WORKSPACE"
-			desktop_, err = xlib.GetWindowProperty(win, "_WIN_WORKSPACE")
+			desktop_, err = xConn.GetWindowProperty(win, "_WIN_WORKSPACE")
 			if err != nil {
 				continue
 			} else if desktop_ != nil && len(desktop_.GetLong()) == 0 {
@@ -94,25 +129,23 @@ func listWindows(includeIcons bool) []window {
 		desktop := int(desktop_.GetLong()[0])
 
 		// Window class
-		class_, err := xlib.GetWindowProperty(win, "WM_CLASS")
+		class_, err := xConn.GetWindowProperty(win, "WM_CLASS")
 		if err != nil {
 			continue
 		} else if class_ != nil && len(class_.GetString()) == 0 {
 			continue
 		}
-		class := strings.Join(class_.GetString(), ".")
-		class = strings.TrimSpace(strings.TrimSuffix(class, "."))
 
 		// Window Title
 		lookForAlternativeProperty = false
-		title_, err := xlib.GetWindowProperty(win, "_NET_WM_NAME")
+		title_, err := xConn.GetWindowProperty(win, "_NET_WM_NAME")
 		if err != nil {
 			lookForAlternativeProperty = true
 		} else if title_ != nil && len(title_.GetString()) == 0 {
 			lookForAlternativeProperty = true
 		}
 		if lookForAlternativeProperty {
-			title_, err = xlib.GetWindowProperty(win, "WM_NAME")
+			title_, err = xConn.GetWindowProperty(win, "WM_NAME")
 			if err != nil {
 				continue
 			} else if title_ != nil && len(title_.GetString()) == 0 {
@@ -121,39 +154,85 @@ func listWindows(includeIcons bool) []window {
 		}
 		title := strings.Join(title_.GetString(), " ")
 
-		// Window Icon
-		var windowIcon *gdk.Pixbuf
-		if includeIcons {
-			originalIcon_ := xlib.GetWindowIcon(win)
-			if originalIcon_ != nil {
-				scaledIcon, err := originalIcon_.ScaleSimple(24, 24, gdk.INTERP_HYPER)
-				if err == nil {
-					windowIcon = scaledIcon
-				}
-			}
-		}
+		windows = append(windows, newClientWindow(fmt.Sprint(windowId), class_.GetString(), title, desktop))
+	}
+	return windows
+}
 
-		window := &window{
-			id:      fmt.Sprint(windowId),
-			class:   class,
-			title:   title,
-			desktop: desktop,
-			desktopName: func() string {
-				if desktop == -1 {
-					return ""
-				}
-				if len(desktopNames) >= 0 && len(desktopNames) >= desktop {
-					return desktopNames[desktop]
-				}
-				return strconv.Itoa(desktop)
-			}(),
-			icon: windowIcon,
+// Function that returns a window with its id, title, desktop and the class, instance and class name of the strings
+// of its WM_CLASS
+func newClientWindow(windowId string, classStrings []string, title string, desktop int) window {
+	class := strings.Join(classStrings, ".")
+	class = strings.TrimSpace(strings.TrimSuffix(class, "."))
+	wmInstance := strings.TrimSpace(classStrings[0])
+	wmClass := wmInstance
+	if len(classStrings) > 1 && len(strings.TrimSpace(classStrings[1])) > 0 {
+		wmClass = strings.TrimSpace(classStrings[1])
+	}
+	return window{
+		id:         windowId,
+		class:      class,
+		wmInstance: wmInstance,
+		wmClass:    wmClass,
+		title:      title,
+		desktop:    desktop,
+	}
+}
+
+// Function that builds the hierarchy of transient windows, the windows transient for a window that is not in the list
+// (e.g. the root window or a hidden group leader) are not transient for any of them
+func linkTransientWindows(windows []window) []window {
+	indexes := map[string]int{}
+	for index, window := range windows {
+		indexes[window.id] = index
+	}
+	for index, window := range windows {
+		if len(window.transientFor) == 0 {
+			continue
+		}
+		parentIndex, exists := indexes[window.transientFor]
+		if !exists || window.transientFor == window.id {
+			windows[index].transientFor = ""
+			continue
 		}
-		windows = append(windows, *window)
+		windows[parentIndex].transients = append(windows[parentIndex].transients, window.id)
 	}
 	return windows
 }
 
+// This function returns the icon of a window scaled to the size used in the lists of windows, nil if it has none
+func getWindowIcon(window xlib.Window) *gdk.Pixbuf {
+	originalIcon_ := xConn.GetWindowIcon(window)
+	if originalIcon_ != nil {
+		scaledIcon, err := originalIcon_.ScaleSimple(24, 24, gdk.INTERP_HYPER)
+		if err == nil {
+			return scaledIcon
+		}
+	}
+	return nil
+}
+
+// This function returns the names of the desktops based on the property "_NET_DESKTOP_NAMES"
+func getDesktopNames() []string {
+	var desktopNames []string
+	netDesktopNamesPropertyResult, _ := xConn.GetWindowProperty(xConn.GetRootWindow(), "_NET_DESKTOP_NAMES")
+	if netDesktopNamesPropertyResult != nil && netDesktopNamesPropertyResult.NumberOfItems > 0 {
+		desktopNames = append(desktopNames, netDesktopNamesPropertyResult.GetString()...)
+	}
+	return desktopNames
+}
+
+// This function returns the name of a desktop, if the desktop has no name its number is returned
+func getDesktopName(desktopNames []string, desktop int) string {
+	if desktop == -1 {
+		return ""
+	}
+	if desktop >= 0 && len(desktopNames) > desktop {
+		return desktopNames[desktop]
+	}
+	return strconv.Itoa(desktop)
+}
+
 // Remove item from a slice of strings
 func removeItem(list []string, item string) []string {
 	for index, value := range list {
@@ -177,6 +256,24 @@ func contains(list []string, string string) bool {
 	return response
 }
 
+/*
+Function that returns the class of a window as it's shown: the class of WM_CLASS when the instance is the same name
+(e.g. "XTerm" for "xterm.XTerm"), both joined otherwise. The instance and the class are kept apart, so dotted names
+like "org.gnome.Nautilus" are not split. The windows read from the *gtk.TreeView only have the class already shown.
+*/
+func (w window) getClass() string {
+	if len(w.wmClass) == 0 {
+		return getClass(w.class)
+	}
+	if strings.EqualFold(w.wmInstance, w.wmClass) {
+		if unicode.IsUpper(rune(w.wmClass[0])) {
+			return w.wmClass
+		}
+		return w.wmInstance
+	}
+	return w.wmInstance + "." + w.wmClass
+}
+
 // Formats the output of WM_CLASS
 func getClass(classString string) string {
 	value := ""
@@ -198,6 +295,32 @@ func getClass(classString string) string {
 	return value
 }
 
+/*
+Function that joins the fields of a value saved in the config file, e.g. a rule or the layout of a window. The config
+getter removes all the spaces and the values of an option are comma separated, so every field gets escaped
+(url.QueryEscape) and the fields are joined with ";".
+*/
+func encodeConfigFields(fields []string) string {
+	escapedFields := make([]string, len(fields))
+	for index, field := range fields {
+		escapedFields[index] = url.QueryEscape(field)
+	}
+	return strings.Join(escapedFields, ";")
+}
+
+// Function that splits the fields of a value saved in the config file with encodeConfigFields
+func decodeConfigFields(value string) ([]string, error) {
+	fields := strings.Split(value, ";")
+	for index, field := range fields {
+		unescapedField, err := url.QueryUnescape(field)
+		if err != nil {
+			return nil, err
+		}
+		fields[index] = unescapedField
+	}
+	return fields, nil
+}
+
 // Function that checks if two window slices are equal
 func funcTestEq(first []window, second []window) bool {
 	if len(first) != len(second) {
@@ -243,11 +366,11 @@ func changeWindowTitle(windowId string, newTitle string) bool {
 	utf8StringType := "UTF8_STRING"
 	stringType := "STRING"
 
-	originalValueNetWMNAmeProp, _ := xlib.GetWindowProperty(windowId_, netWMProp)
-	originalValueWMNameProp, _ := xlib.GetWindowProperty(windowId_, wmProp)
+	originalValueNetWMNAmeProp, _ := xConn.GetWindowProperty(windowId_, netWMProp)
+	originalValueWMNameProp, _ := xConn.GetWindowProperty(windowId_, wmProp)
 
 	funcChangeWindowTitle := func(property string, typeOfProperty string, value string) bool {
-		result, err := xlib.ChangeWindowProperty(windowId_, property, typeOfProperty, 8, "PropModeReplace", value)
+		result, err := xlib.ChangeWindowProperty(xConn, windowId_, property, typeOfProperty, 8, "PropModeReplace", value)
 		if err != nil {
 			return false
 		}
@@ -276,12 +399,27 @@ func changeWindowTitle(windowId string, newTitle string) bool {
 	return finalResult && netWMNameResult && WMNameResult
 }
 
+// Function that returns the geometry of the frame of a window as a string (WIDTHxHEIGHT+X+Y), empty if it can't be read
+func getWindowGeometry(window xlib.Window) string {
+	geometry, err := xConn.GetWindowGeometry(window)
+	if geometry == nil || err != nil {
+		return ""
+	}
+	return geometry.Frame.String()
+}
+
+// Function that converts the id of a window to a xlib.Window
+func getXWindow(windowId string) xlib.Window {
+	id, _ := strconv.ParseUint(windowId, 10, 64)
+	return xlib.Window(id)
+}
+
 //-------------------------------------------------- CALLBACKS GLOBAL HOTKEYS -------------------------------------------
 
 // Function to move between windows following the current order, it can go backwards or forwards
 func (mainGUI *MainGUI) moveNextWindow(backwards bool, times int) {
 	fmt.Printf("(Callback) moveNextWindow(backwards: %t)\n", backwards)
-	result, currentWindow_ := xlib.GetActiveWindow()
+	result, currentWindow_ := xConn.GetActiveWindow()
 	if !result || currentWindow_ == xlib.CURRENTWINDOW {
 		times++
 		if times == 5 {
@@ -295,33 +433,45 @@ func (mainGUI *MainGUI) moveNextWindow(backwards bool, times int) {
 	if len(currentOrder) == 1 && currentWindow == currentOrder[0].id {
 		return
 	}
-	var nextIndex int
-	if backwards {
-		nextIndex = currentIndex - 1
-		if nextIndex < 0 {
+	// Anonymous function that returns the index that follows another one in the current order
+	funcGetNextIndex := func(index int) int {
+		if backwards {
+			index--
+			if index < 0 {
+				index = len(currentOrder) - 1
+			}
+		} else {
+			index++
+			if index >= len(currentOrder) {
+				index = 0
+			}
+		}
+		return index
+	}
+	index, restart := getRotationAnchor()
+	nextIndex := funcGetNextIndex(index)
+	if restart { // The focus is outside the rotation, it starts again from the beginning
+		nextIndex = 0
+		if backwards {
 			nextIndex = len(currentOrder) - 1
 		}
-	} else {
-		nextIndex = currentIndex + 1
-		if nextIndex >= len(currentOrder) {
-			nextIndex = 0
+	}
+	// The windows out of the rotation scope are skipped, they stay in the current order
+	scope := newScopeFilter()
+	for skipped := 0; !scope.contains(currentOrder[nextIndex]); skipped++ {
+		if skipped == len(currentOrder) {
+			fmt.Println("(Callback) No window of the current order is in the rotation scope:", scope.scope)
+			return
 		}
+		nextIndex = funcGetNextIndex(nextIndex)
 	}
-	if currentOrder[currentIndex].id == currentOrder[nextIndex].id &&
+	if currentOrder[index].id == currentOrder[nextIndex].id &&
 		strings.Contains(currentOrder[nextIndex].class, "cloned") {
-		currentIndex = nextIndex
+		setRotationAnchor(nextIndex)
+		showOverlay(currentOrder, nextIndex, true)
 		return
 	}
-	isNextWindowValid := false
-	for _, windowActive := range listWindows(false) {
-		if strings.Contains(windowActive.class, mainGUI.application.GetApplicationID()) || windowActive.desktop == -1 {
-			continue
-		}
-		if windowActive.id == currentOrder[nextIndex].id {
-			isNextWindowValid = true
-			break
-		}
-	}
+	isNextWindowValid := isWindowOpen(currentOrder[nextIndex].id)
 	recursiveCall := false // Wether the function should call itself again
 	if isNextWindowValid {
 		fmt.Println("(Callback) Next window:", currentOrder[nextIndex].windowToString())
@@ -330,10 +480,16 @@ func (mainGUI *MainGUI) moveNextWindow(backwards bool, times int) {
 			id, _ := strconv.Atoi(currentOrder[nextIndex].id)
 			return id
 		}())
-		if xlib.ActivateWindow(nextWindow) {
-			if xlib.WaitForWindowActivate(nextWindow, true) {
-				currentIndex = nextIndex
-			}
+		err := activateWindow(nextWindow)
+		if err == nil {
+			setRotationAnchor(nextIndex)
+			showOverlay(currentOrder, nextIndex, true)
+		} else if xlib.IsWindowGone(err) {
+			// The window was closed after it was validated
+			fmt.Println("(Callback) Next window:", currentOrder[nextIndex], "IS GONE:", err)
+			recursiveCall = true
+		} else {
+			fmt.Println("ERROR ACTIVATING WINDOW: ", err)
 		}
 	} else {
 		fmt.Println("(Callback) Next window:", currentOrder[nextIndex], "IS NOT VALID")
@@ -350,18 +506,73 @@ func (mainGUI *MainGUI) moveNextWindow(backwards bool, times int) {
 	}
 }
 
+/*
+Function that activates the window in a position (slot) of the current order, the position of the current order
+becomes that one. Callback of global hotkey
+
+Parameters:
+  - slot: Index of the window in the current order, a negative one counts from the end (-1 is the last window)
+*/
+func (mainGUI *MainGUI) goToSlot(slot int) {
+	fmt.Printf("(Callback) goToSlot(slot: %d)\n", slot)
+	if slot < 0 {
+		slot += len(currentOrder)
+	}
+	if slot < 0 || slot >= len(currentOrder) {
+		fmt.Println("(Callback) The current order has no window in slot:", slot+1)
+		return
+	}
+	window := currentOrder[slot]
+	if isWindowOpen(window.id) {
+		fmt.Println("(Callback) Window in slot:", window.windowToString())
+		err := activateWindow(getXWindow(window.id))
+		if err == nil {
+			setRotationAnchor(slot)
+			showOverlay(currentOrder, slot, true)
+			return
+		} else if !xlib.IsWindowGone(err) {
+			fmt.Println("ERROR ACTIVATING WINDOW: ", err)
+			return
+		}
+		// The window was closed after it was validated
+	}
+	fmt.Println("(Callback) Window in slot:", window, "IS NOT VALID")
+	glib.IdleAdd(func() {
+		// Emit signal to delete invalid window, the next windows move up one slot
+		_, _ = mainGUI.application.Emit(signalDeleteRow, glib.TYPE_NONE, strconv.Itoa(slot))
+	})
+}
+
 // Function to move to the next window (forwards). Callback of global hotkey
 func (mainGUI *MainGUI) moveForwards() {
-	times := 0
-	if len(currentOrder) > 0 {
-		mainGUI.moveNextWindow(false, times)
-	}
+	mainGUI.moveWindowWithMode(moveForwards, false)
 }
 
 // Function to move to the next window (backwards). Callback of global hotkey
 func (mainGUI *MainGUI) moveBackwards() {
-	times := 0
-	if len(currentOrder) > 0 {
-		mainGUI.moveNextWindow(true, times)
+	mainGUI.moveWindowWithMode(moveBackwards, true)
+}
+
+// Function that toggles a state (maximized, fullscreen, above...) of the active window. Callback of global hotkey
+func (mainGUI *MainGUI) toggleActiveWindowState(state xlib.WindowState) {
+	result, activeWindow := xConn.GetActiveWindow()
+	if !result || activeWindow == xlib.CURRENTWINDOW {
+		fmt.Println("ERROR OBTAINING CURRENT WINDOW")
+		return
+	}
+	if _, err := xConn.ChangeWindowState(activeWindow, xlib.StateToggle, state); err != nil {
+		fmt.Println("ERROR CHANGING WINDOW STATE: ", err)
+	}
+}
+
+// Function that minimizes the active window. Callback of global hotkey
+func (mainGUI *MainGUI) minimizeActiveWindow() {
+	result, activeWindow := xConn.GetActiveWindow()
+	if !result || activeWindow == xlib.CURRENTWINDOW {
+		fmt.Println("ERROR OBTAINING CURRENT WINDOW")
+		return
+	}
+	if _, err := xConn.IconifyWindow(activeWindow); err != nil {
+		fmt.Println("ERROR MINIMIZING WINDOW: ", err)
 	}
 }
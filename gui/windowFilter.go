@@ -0,0 +1,78 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"linux-windows-switcher/libs/xlib"
+)
+
+// Section and options from config file of the filter of windows by their EWMH type and state
+const (
+	sectionWindowFilter = "filter"
+	optionSkippedTypes  = "skip_types"   // Types of the windows that are never in the rotation, "none" to keep all of them
+	optionSkipTaskbar   = "skip_taskbar" // Whether the windows with the state "_NET_WM_STATE_SKIP_TASKBAR" are skipped
+	optionSkipPager     = "skip_pager"   // Whether the windows with the state "_NET_WM_STATE_SKIP_PAGER" are skipped
+
+	valueNoSkippedTypes = "none"
+)
+
+// Config of the filter of windows by their EWMH type and state, it's only written when the config is loaded
+var (
+	skippedWindowTypes = []xlib.WindowType{
+		xlib.TypeDesktop,
+		xlib.TypeDock,
+		xlib.TypeToolbar,
+		xlib.TypeMenu,
+		xlib.TypeUtility,
+		xlib.TypeSplash,
+		xlib.TypeDropdownMenu,
+		xlib.TypePopupMenu,
+		xlib.TypeTooltip,
+		xlib.TypeNotification,
+		xlib.TypeCombo,
+		xlib.TypeDND,
+	}
+	skipTaskbarWindows = true
+	skipPagerWindows   = true
+)
+
+/*
+Function that loads from config file the filter of windows by their EWMH type and state, the defaults are kept for the
+options that are not set.
+
+Parameters:
+  - getConfig: Function that returns the value of an option of the config file, empty if it doesn't exist
+*/
+func loadWindowFilterConfig(getConfig func(section string, option string) string) {
+	if value := getConfig(sectionWindowFilter, optionSkippedTypes); value == valueNoSkippedTypes {
+		skippedWindowTypes = nil
+	} else if len(value) > 0 {
+		var windowTypes []xlib.WindowType
+		for _, windowType := range strings.Split(value, ",") {
+			if slices.Contains(xlib.WindowTypes, xlib.WindowType(windowType)) {
+				windowTypes = append(windowTypes, xlib.WindowType(windowType))
+			} else if len(windowType) > 0 {
+				fmt.Println("ERROR UNKNOWN WINDOW TYPE IN CONFIG FILE: ", windowType)
+			}
+		}
+		skippedWindowTypes = windowTypes
+	}
+
+	if skip, err := strconv.ParseBool(getConfig(sectionWindowFilter, optionSkipTaskbar)); err == nil {
+		skipTaskbarWindows = skip
+	}
+	if skip, err := strconv.ParseBool(getConfig(sectionWindowFilter, optionSkipPager)); err == nil {
+		skipPagerWindows = skip
+	}
+}
+
+// Function that returns the name of a window type to show it on the GUI
+func getWindowTypeName(windowType xlib.WindowType) string {
+	if len(windowType) == 0 {
+		return ""
+	}
+	return funcGetStringResource("window_type_" + string(windowType))
+}
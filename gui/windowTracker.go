@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"linux-windows-switcher/libs/xlib"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// trackedWindow Properties of an open client window, they are kept up to date by the events of the X server
+type trackedWindow struct {
+	class      []string // Strings of WM_CLASS
+	title      string
+	desktop    int
+	hasDesktop bool
+}
+
+// Client windows currently open, in the order they were opened. Structure: key: Id, value: Properties
+var (
+	trackedWindows        = map[string]*trackedWindow{}
+	trackedWindowIds      []string
+	trackedWindowsMutex   sync.RWMutex
+	windowTrackerActive   bool // Whether the tracker knows the whole client list, set once the initial one was received
+	windowTrackerInstance *xlib.Subscription
+	ownApplicationId      string // Id of the application, its windows are never in the rotation
+)
+
+// Function that subscribes to the events of the X server to keep track of the open windows
+func (mainGUI *MainGUI) startWindowTracker() {
+	ownApplicationId = mainGUI.application.GetApplicationID()
+	subscription, err := xlib.Subscribe()
+	if err != nil {
+		fmt.Println("ERROR STARTING WINDOW TRACKER, falling back to polling: ", err)
+		return
+	}
+	windowTrackerInstance = subscription
+
+	go func() {
+		for event := range subscription.Events() {
+			windowId := strconv.FormatUint(uint64(event.EventWindow()), 10)
+			switch event := event.(type) {
+			case xlib.WindowAddedEvent:
+				trackedWindowsMutex.Lock()
+				if _, exists := trackedWindows[windowId]; !exists {
+					trackedWindowIds = append(trackedWindowIds, windowId)
+				}
+				trackedWindows[windowId] = &trackedWindow{
+					class:      event.Class,
+					title:      event.Title,
+					desktop:    event.Desktop,
+					hasDesktop: event.HasDesktop,
+				}
+				trackedWindowsMutex.Unlock()
+				trackWindowFrame(windowId, event.Window)
+			case xlib.ClientsSyncedEvent:
+				trackedWindowsMutex.Lock()
+				windowTrackerActive = true
+				trackedWindowsMutex.Unlock()
+			case xlib.WindowRemovedEvent:
+				trackedWindowsMutex.Lock()
+				delete(trackedWindows, windowId)
+				trackedWindowIds = removeItem(trackedWindowIds, windowId)
+				trackedWindowsMutex.Unlock()
+				forgetFocus(windowId)
+				forgetWindowFrame(windowId)
+			case xlib.ActiveWindowChangedEvent:
+				if event.Window != xlib.Window(0) {
+					// A transient window (dialog) gets the focus on behalf of the window it's transient for
+					windowId = getTransientRootId(windowId)
+					recordFocus(windowId)
+				}
+				glib.IdleAdd(func() { anchorCurrentIndex(windowId) })
+			case xlib.WindowTitleChangedEvent:
+				if len(event.Title) == 0 {
+					continue
+				}
+				trackedWindowsMutex.Lock()
+				if tracked, exists := trackedWindows[windowId]; exists {
+					tracked.title = event.Title
+				}
+				trackedWindowsMutex.Unlock()
+				glib.IdleAdd(func() { mainGUI.contentTabVentanas.windowList.updateWindowTitle(windowId, event.Title) })
+			case xlib.WindowGeometryChangedEvent:
+				trackWindowFrame(windowId, event.Window)
+				geometry := getWindowGeometry(event.Window)
+				glib.IdleAdd(func() { mainGUI.contentTabVentanas.windowList.updateWindowGeometry(windowId, geometry) })
+			case xlib.WindowDesktopChangedEvent:
+				trackedWindowsMutex.Lock()
+				if tracked, exists := trackedWindows[windowId]; exists {
+					tracked.desktop, tracked.hasDesktop = event.Desktop, true
+				}
+				trackedWindowsMutex.Unlock()
+				glib.IdleAdd(func() {
+					mainGUI.contentTabVentanas.windowList.updateWindowDesktop(
+						windowId,
+						event.Desktop,
+						getDesktopName(getDesktopNames(), event.Desktop),
+					)
+				})
+			case xlib.MonitorAddedEvent, xlib.MonitorRemovedEvent, xlib.MonitorChangedEvent:
+				updateMonitors(event)
+			}
+		}
+		trackedWindowsMutex.Lock()
+		windowTrackerActive = false
+		trackedWindowsMutex.Unlock()
+	}()
+}
+
+// StopWindowTracker Stops the subscription to the events of the X server
+func (mainGUI *MainGUI) StopWindowTracker() {
+	if windowTrackerInstance != nil {
+		windowTrackerInstance.Close()
+	}
+}
+
+// Function that returns true if an open window can be in the rotation: the windows of the switcher itself and the
+// ones with desktop -1 (e.g. panels) can't
+func isRotationCandidate(class string, desktop int) bool {
+	return !strings.Contains(class, ownApplicationId) && desktop != -1
+}
+
+/*
+Function that returns the open client windows known by the tracker with their id, class, title and desktop, the
+windows without any of them are left out like in scanClientWindows.
+
+Returns:
+  - The windows, in the order they were opened
+  - Whether the tracker is running and knows the whole client list, if not the windows must be read from the X server
+*/
+func getTrackedWindows() ([]window, bool) {
+	trackedWindowsMutex.RLock()
+	defer trackedWindowsMutex.RUnlock()
+	if !windowTrackerActive {
+		return nil, false
+	}
+	var windows []window
+	for _, windowId := range trackedWindowIds {
+		if tracked := trackedWindows[windowId]; tracked.isListed() {
+			windows = append(windows, newClientWindow(windowId, tracked.class, tracked.title, tracked.desktop))
+		}
+	}
+	return windows, true
+}
+
+// Function that returns true if a tracked window has a class, a title and a desktop, the ones listWindows returns
+func (tracked *trackedWindow) isListed() bool {
+	return len(tracked.class) > 0 && len(tracked.title) > 0 && tracked.hasDesktop
+}
+
+/*
+Function that returns true if a window is currently open and it can be in the rotation (see isRotationCandidate), it
+queries the X server only if the tracker is not running
+*/
+func isWindowOpen(windowId string) bool {
+	trackedWindowsMutex.RLock()
+	if windowTrackerActive {
+		defer trackedWindowsMutex.RUnlock()
+		tracked, exists := trackedWindows[windowId]
+		return exists && tracked.isListed() && isRotationCandidate(strings.Join(tracked.class, "."), tracked.desktop)
+	}
+	trackedWindowsMutex.RUnlock()
+
+	for _, windowActive := range scanClientWindows() {
+		if !isRotationCandidate(windowActive.class, windowActive.desktop) {
+			continue
+		}
+		if windowActive.id == windowId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package keyboard
+
+import (
+	"fmt"
+	"sync"
+
+	"linux-windows-switcher/libs/xlib"
+)
+
+// Amount of hotkey callbacks that can be queued before the events of the key grabber stop being read
+const callbacksBufferSize = 64
+
+var (
+	// Key grabber used by the backend "grab", it is nil when the backend "hook" is used
+	keyGrabber *xlib.KeyGrabber
+	// Hotkeys grabbed on the X server, the index of every hotkey matches the index reported by the key grabber. It's
+	// guarded by hotKeysMutex
+	grabbedHotKeys []*HotKey
+	// Serializes the grabs, the hotkeys mutex is not held while grabbing since the key grabber may be waiting for its
+	// events to be read
+	grabMutex sync.Mutex
+)
+
+// Initializes the key grabber that listens to the global hotkeys registered with XGrabKey
+func (listenerKeyboard *ListenerKeyboard) startKeyGrabber() error {
+	grabber, err := xlib.NewKeyGrabber()
+	if err != nil {
+		return err
+	}
+	keyGrabber = grabber
+
+	// The callbacks run in their own goroutine, in the order the hotkeys were pressed, so a slow callback doesn't stop
+	// the events of the key grabber from being read
+	callbacks := make(chan func(), callbacksBufferSize)
+	go func() {
+		for callback := range callbacks {
+			callback()
+		}
+	}()
+
+	go func() {
+		defer close(callbacks)
+		for event := range grabber.Events() {
+			hotKeysMutex.RLock()
+			var hotKey *HotKey
+			if event.Index < len(grabbedHotKeys) {
+				hotKey = grabbedHotKeys[event.Index]
+			}
+			hotKeysMutex.RUnlock()
+			if debug {
+				fmt.Printf("DEBUG: grabbed key combination %d, released: %t\n", event.Index, event.Released)
+			}
+			if event.Released {
+				if hotKey != nil && hotKey.OnRelease != nil {
+					callbacks <- hotKey.OnRelease
+				}
+				continue
+			}
+			if hotKey != nil && !hotKey.Disabled {
+				fmt.Printf("\nGLOBAL HOTKEY PRESSED: %s\n", hotKey.HotKeys)
+				callbacks <- hotKey.Callback
+			}
+		}
+	}()
+	return nil
+}
+
+/*
+Registers the enabled hotkeys on the X server. The hotkeys mutex must not be held by the caller, it's only taken to
+publish the grabbed hotkeys and their errors once the grab is done.
+
+Parameters:
+  - hotKeysInput: Hotkeys to grab, the ones disabled or without keys are not grabbed
+*/
+func grabHotKeys(hotKeysInput []*HotKey) {
+	grabMutex.Lock()
+	defer grabMutex.Unlock()
+
+	var toGrab []*HotKey
+	var combinations [][]uint
+	var hold []bool
+	for _, hotKey := range hotKeysInput {
+		if hotKey.Disabled || len(hotKey.HotKeysKeyCodes) == 0 {
+			continue
+		}
+		toGrab = append(toGrab, hotKey)
+		combinations = append(combinations, hotKey.HotKeysKeyCodes)
+		hold = append(hold, hotKey.Hold)
+	}
+	// The events of the previous grab are ignored while grabbing, their indexes don't match the new hotkeys
+	hotKeysMutex.Lock()
+	grabbedHotKeys = nil
+	hotKeysMutex.Unlock()
+
+	errs := keyGrabber.GrabKeys(combinations, hold)
+
+	hotKeysMutex.Lock()
+	defer hotKeysMutex.Unlock()
+	for _, hotKey := range hotKeysInput {
+		hotKey.GrabError = nil
+	}
+	for index, err := range errs {
+		if err != nil {
+			fmt.Printf("ERROR GRABBING GLOBAL HOTKEY %s: %s\n", toGrab[index].HotKeys, err)
+			toGrab[index].GrabError = err
+		}
+	}
+	grabbedHotKeys = toGrab
+}
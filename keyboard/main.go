@@ -2,6 +2,9 @@ package keyboard
 
 import (
 	"fmt"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/gotk3/gotk3/glib"
 
@@ -14,6 +17,7 @@ type ListenerKeyboard struct {
 	application   *gtk.Application
 	active        bool
 	listenerState bool
+	backend       string
 }
 
 type HotKey struct {
@@ -21,7 +25,10 @@ type HotKey struct {
 	HotKeys         []string
 	HotKeysKeyCodes []uint
 	Disabled        bool
+	GrabError       error  // Error returned by the X server when grabbing the HotKeys, nil if they were grabbed
 	Callback        func() // Callback func, it gets called when the HotKeys of the HotKey are pressed
+	Hold            bool   // Whether the modifiers are held, the last key can be pressed again until they are released
+	OnRelease       func() // Callback func of a held HotKey, it gets called when its modifiers are released
 }
 
 const (
@@ -29,14 +36,30 @@ const (
 	signalControlListener   = "app-listener-keyboard"
 	signalSetHotKeys        = "app-listener-set-hotkeys"
 	signalSyncStateListener = "app-listener-sync-state"
+	signalGetConfig         = "app-get-config"
+
+	// Section and option from config file related with the backend of the listener
+	sectionHotKeys = "hotkeys"
+	optionBackend  = "backend"
+
+	// Backends available to listen to the global hotkeys
+	BackendGrab = "grab" // Every hotkey is registered with XGrabKey, the keystroke is consumed
+	BackendHook = "hook" // Every key event is watched with gohook, the keystroke reaches the focused application
 )
 
 var (
 	// Slice of global hotkey objects
-	hotKeys []*HotKey
+	hotKeys      []*HotKey
+	hotKeysMutex sync.RWMutex
 
 	// Pressed keys
 	keys = map[uint16]bool{}
+	// Held hotkey whose modifiers are still pressed and the key that triggered it, used by the backend "hook"
+	heldHotKey     *HotKey
+	heldTriggerKey uint16
+	// Time of the last keyboard or mouse event seen by gohook, used by the backend "hook"
+	lastInput      time.Time
+	lastInputMutex sync.Mutex
 	// Channel used to communicate with the goroutine listening to keyboard events
 	mainChannel = make(chan bool)
 	debug       bool
@@ -45,7 +68,13 @@ var (
 // NewListenerKeyBoard constructor
 func NewListenerKeyBoard(application *gtk.Application, debug_ bool) *ListenerKeyboard {
 	debug = debug_
-	listenerKeyboard := &ListenerKeyboard{application: application}
+	listenerKeyboard := &ListenerKeyboard{application: application, backend: BackendGrab}
+
+	// Backend of the listener from config file
+	result, _ := application.Emit(signalGetConfig, glib.TYPE_STRING, sectionHotKeys, optionBackend)
+	if backend, ok := result.(string); ok && backend == BackendHook {
+		listenerKeyboard.backend = BackendHook
+	}
 
 	listenerKeyboard.setupContentKeyboard()
 	return listenerKeyboard
@@ -72,11 +101,24 @@ func (listenerKeyboard *ListenerKeyboard) setupContentKeyboard() {
 					if len(keys) > 0 {
 						keys = map[uint16]bool{}
 					}
+					heldHotKey = nil
+					// The grabbed hotkeys are released so the keys reach the applications again
+					if keyGrabber != nil {
+						keyGrabber.UngrabAll()
+					}
 				}
 				listenerKeyboard.active = active
 			})
 		},
 	)
+	if listenerKeyboard.backend == BackendGrab {
+		err := listenerKeyboard.startKeyGrabber()
+		if err == nil {
+			return
+		}
+		fmt.Println("ERROR STARTING KEY GRABBER, falling back to gohook: ", err)
+		listenerKeyboard.backend = BackendHook
+	}
 	listenerKeyboard.startKeyboardListener()
 	mainChannel <- true
 }
@@ -100,6 +142,9 @@ func (listenerKeyboard *ListenerKeyboard) startKeyboardListener() {
 					channel := hook.Start()
 					listenerActive = true
 					for evento := range channel {
+						lastInputMutex.Lock()
+						lastInput = time.Now()
+						lastInputMutex.Unlock()
 						if debug && (evento.Kind == hook.KeyDown || evento.Kind == hook.KeyHold || evento.Kind == hook.KeyUp) {
 							fmt.Println("DEBUG: ", evento)
 						}
@@ -111,10 +156,11 @@ func (listenerKeyboard *ListenerKeyboard) startKeyboardListener() {
 							teclaDownOrHold = evento.Rawcode
 							keys[evento.Rawcode] = true // Update the map to indicate the key is pressed
 							// Every time a key is pressed we check if the global hotkey was activated
-							checkKeysPressed()
+							checkKeysPressed(evento.Rawcode)
 						} else if listenerKeyboard.active && (evento.Kind == hook.KeyUp) {
 							keys[evento.Rawcode] = false // Update the map to indicate the key is not pressed anymore
 							teclaDownOrHold = 0
+							checkHeldHotKeyReleased(evento.Rawcode)
 							// Delete all non-active entries from the map
 							for index, key := range keys {
 								if !key {
@@ -138,11 +184,31 @@ func ExitListener() {
 		}
 		fmt.Println("RECOVER: ", err)
 	}()
+	if keyGrabber != nil {
+		keyGrabber.Close()
+		return
+	}
 	mainChannel <- false
 	close(mainChannel)
 	hook.End()
 }
 
+// IdleTime Time since the last keyboard or mouse input seen by the listener, it's read from the X server when the
+// backend "grab" is used since the grabbed keys are the only events it receives
+func IdleTime() time.Duration {
+	if keyGrabber != nil {
+		if idle, err := keyGrabber.IdleTime(); err == nil {
+			return idle
+		}
+	}
+	lastInputMutex.Lock()
+	defer lastInputMutex.Unlock()
+	if lastInput.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Since(lastInput)
+}
+
 // NewHotKey Constructor HotKey
 func NewHotKey(name string, callback func()) *HotKey {
 	hotkey := &HotKey{
@@ -153,9 +219,15 @@ func NewHotKey(name string, callback func()) *HotKey {
 	return hotkey
 }
 
-// SetHotKeys sets the hotkeys for the keyboard listener
+// SetHotKeys sets the hotkeys for the keyboard listener. With the backend "grab" the hotkeys get registered on the
+// X server and the field GrabError of every hotkey is updated
 func SetHotKeys(hotKeysInput []*HotKey) {
+	hotKeysMutex.Lock()
 	hotKeys = hotKeysInput
+	hotKeysMutex.Unlock()
+	if keyGrabber != nil {
+		grabHotKeys(hotKeysInput)
+	}
 }
 
 // Function that checks if any global hotkey was pressed to trigger its callback
@@ -172,13 +244,40 @@ func allPressed(pressed map[uint16]bool, keys []uint) bool {
 }
 
 // Loop through the hotkeys to find out if any has been activated to trigger its callback
-func checkKeysPressed() {
+func checkKeysPressed(pressedKey uint16) {
+	hotKeysMutex.RLock()
+	defer hotKeysMutex.RUnlock()
 	for _, hotKey := range hotKeys {
 		// If the global hotkey is not disabled and the keys are pressed its callback gets triggered
 		if !hotKey.Disabled && allPressed(keys, hotKey.HotKeysKeyCodes) {
 			fmt.Printf("\nGLOBAL HOTKEY PRESSED: %s\n", hotKey.HotKeys)
 			hotKey.Callback()
+			if hotKey.Hold && heldHotKey == nil {
+				if len(hotKey.HotKeysKeyCodes) > 1 {
+					heldHotKey = hotKey
+					heldTriggerKey = pressedKey
+				} else if hotKey.OnRelease != nil { // There are no modifiers to hold
+					hotKey.OnRelease()
+				}
+			}
 			break
 		}
 	}
 }
+
+// Checks if a key released was one of the modifiers of the held hotkey to trigger its release callback
+func checkHeldHotKeyReleased(releasedKey uint16) {
+	if heldHotKey == nil || releasedKey == heldTriggerKey {
+		return
+	}
+	for _, key := range heldHotKey.HotKeysKeyCodes {
+		if uint16(key) == releasedKey {
+			hotKey := heldHotKey
+			heldHotKey = nil
+			if hotKey.OnRelease != nil {
+				hotKey.OnRelease()
+			}
+			return
+		}
+	}
+}
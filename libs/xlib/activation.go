@@ -0,0 +1,217 @@
+package xlib
+
+//#include <X11/Xlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// ActivationStrategy Way of asking the window manager (or the X server) to activate a window.
+type ActivationStrategy string
+
+const (
+	// ActivationEWMH Client message "_NET_ACTIVE_WINDOW" with a fresh timestamp of the X server, so window managers
+	// with focus-stealing prevention don't take it as an old request
+	ActivationEWMH ActivationStrategy = "ewmh"
+	// ActivationRaiseFocus XRaiseWindow on the frame of the window followed by XSetInputFocus, bypassing the window
+	// manager
+	ActivationRaiseFocus ActivationStrategy = "raise_focus"
+	// ActivationUnmapMap Withdraws and maps the window again so the window manager manages it as a new window, meant
+	// as a last resort. Its desktop and states are kept, but the window manager may place it somewhere else
+	ActivationUnmapMap ActivationStrategy = "unmap_map"
+)
+
+// ErrActivationTimeout The window didn't become (or stop being) the active window before the timeout
+var ErrActivationTimeout = errors.New("timeout waiting for the active window to change")
+
+const (
+	// Milliseconds between every query of the active window while waiting for it to change
+	activationPollInterval = 30
+
+	// Milliseconds the window manager has to withdraw a window before it's mapped again
+	withdrawTimeout = 250
+)
+
+/*
+ActivateWindowWith Activates a window using a given strategy, changing the current desktop first if the window is on
+another desktop. The request being sent doesn't mean the window got activated, use WaitForWindowActivate for that.
+
+Parameters:
+  - window: Specifies the window to activate.
+  - strategy: Specifies the strategy used to activate the window.
+
+Returns:
+  - A boolean indicating if the requests were sent
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) ActivateWindowWith(window Window, strategy ActivationStrategy) (bool, error) {
+	conn.lock()
+	defer conn.unlock()
+	// Try to change current desktop to the window's desktop if we're not in the same desktop
+	if windowDesktopProp, _ := conn.GetWindowProperty(window, "_NET_WM_DESKTOP"); windowDesktopProp != nil &&
+		windowDesktopProp.NumberOfItems > 0 {
+		if _, currentDesktop := conn.GetCurrentDesktop(); currentDesktop >= 0 {
+			windowDesktop := int(windowDesktopProp.longResult[0])
+			if windowDesktop >= 0 && currentDesktop != windowDesktop {
+				fmt.Printf("Changing current desktop from %d to %d\n", currentDesktop, windowDesktop)
+				conn.ChangeCurrentDesktop(int(windowDesktopProp.longResult[0]))
+			}
+		}
+	}
+
+	windowAttributes, err := conn.GetWindowAttributes(window)
+	if windowAttributes == nil && err != nil {
+		return false, err
+	} else if windowAttributes.Screen == nil {
+		return false, nil
+	}
+
+	timestamp, err := conn.getServerTime()
+	if err != nil {
+		fmt.Println("ERROR GETTING TIMESTAMP FROM THE X SERVER, using CurrentTime: ", err)
+		timestamp = C.CurrentTime
+	}
+
+	switch strategy {
+	case ActivationEWMH:
+		_, activeWindow := conn.GetActiveWindow()
+		// Source indication 2 (pager), the request comes from a direct user action
+		return conn.SendClientMessage(window, "_NET_ACTIVE_WINDOW", 2, int64(timestamp), int64(activeWindow))
+	case ActivationRaiseFocus:
+		frame := conn.getFrameWindow(window)
+		trap := trapErrors(conn.display)
+		C.XRaiseWindow(conn.display, frame)
+		C.XSetInputFocus(conn.display, window, C.RevertToParent, timestamp)
+		if err := trap.untrap("XSetInputFocus", true); err != nil {
+			return false, err
+		}
+		return true, nil
+	case ActivationUnmapMap:
+		return conn.remapWindow(window)
+	}
+	return false, fmt.Errorf("the activation strategy \"%s\" does not exist", strategy)
+}
+
+/*
+Withdraws a window and maps it again raised, so the window manager manages it as a new window. The window is withdrawn
+as the ICCCM (section 4.1.4) asks, unmapping it and sending a synthetic UnmapNotify to the root window
+(XWithdrawWindow), otherwise a reparenting window manager may take it as iconified or lose its desktop and states. The
+window manager removes "_NET_WM_DESKTOP" and "_NET_WM_STATE" from a withdrawn window, so they are set again before
+mapping it and the window manager reads them as the ones of a new window (EWMH).
+
+Returns:
+  - A boolean indicating if the requests were sent
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) remapWindow(window Window) (bool, error) {
+	desktop, _ := conn.GetWindowProperty(window, "_NET_WM_DESKTOP")
+	states, _ := conn.GetWindowProperty(window, "_NET_WM_STATE")
+
+	trap := trapErrors(conn.display)
+	C.XWithdrawWindow(conn.display, window, C.XDefaultScreen(conn.display))
+	C.XSync(conn.display, C.False)
+	if err := trap.untrap("XWithdrawWindow", true); err != nil {
+		return false, err
+	}
+	// The window manager sets "WM_STATE" to WithdrawnState (0) or removes it once the window is withdrawn
+	deadline := time.Now().Add(time.Millisecond * withdrawTimeout)
+	for time.Now().Before(deadline) {
+		wmState, err := conn.GetWindowProperty(window, "WM_STATE")
+		if err != nil || wmState == nil || len(wmState.GetLong()) == 0 || wmState.GetLong()[0] == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond * activationPollInterval)
+	}
+
+	if desktop != nil && len(desktop.GetLong()) > 0 {
+		_, _ = ChangeWindowProperty(conn, window, "_NET_WM_DESKTOP", "CARDINAL", 32, "PropModeReplace", desktop.GetLong()[:1])
+	}
+	if states != nil && len(states.GetLong()) > 0 {
+		_, _ = ChangeWindowProperty(conn, window, "_NET_WM_STATE", "ATOM", 32, "PropModeReplace", states.GetLong())
+	}
+	trap = trapErrors(conn.display)
+	C.XMapRaised(conn.display, window)
+	if err := trap.untrap("XMapRaised", true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+WaitForWindowActivate Waits until a window becomes the active window (or stops being it).
+
+Parameters:
+  - window: Specifies the window to wait for.
+  - active: Whether to wait for the window to become the active window or to stop being it.
+  - timeout: Specifies how long to wait.
+
+Returns:
+  - nil if the active window changed as expected, ErrActivationTimeout otherwise
+*/
+func (conn *Conn) WaitForWindowActivate(window Window, active bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if result, activeWindow := conn.GetActiveWindow(); result {
+			if active && activeWindow == window {
+				return nil
+			}
+			if !active && activeWindow != Window(0) && activeWindow != window {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w (window %d, active: %t)", ErrActivationTimeout, window, active)
+		}
+		time.Sleep(time.Millisecond * activationPollInterval)
+	}
+}
+
+// Returns the ancestor of a window that is a child of the root window, the frame of the window manager if it reparents
+func (conn *Conn) getFrameWindow(window Window) Window {
+	frame := window
+	for {
+		queryTreeResult, err := conn.QueryTree(frame)
+		if queryTreeResult == nil || err != nil || queryTreeResult.Parent == queryTreeResult.Root ||
+			queryTreeResult.Parent == Window(0) {
+			return frame
+		}
+		frame = queryTreeResult.Parent
+	}
+}
+
+// Gets the current timestamp of the X server, it changes the property "_NET_WM_USER_TIME" on a window owned by the
+// connection and reads the time of the PropertyNotify event. The display must be locked.
+func (conn *Conn) getServerTime() (C.Time, error) {
+	trap := trapErrors(conn.display)
+	if conn.timeWindow == Window(0) {
+		conn.timeWindow = C.XCreateSimpleWindow(conn.display, conn.root, -1, -1, 1, 1, 0, 0, 0)
+		C.XSelectInput(conn.display, conn.timeWindow, C.PropertyChangeMask)
+	}
+	propertyName := C.CString("_NET_WM_USER_TIME")
+	defer C.XFree(unsafe.Pointer(propertyName))
+	propertyTypeName := C.CString("CARDINAL")
+	defer C.XFree(unsafe.Pointer(propertyTypeName))
+	C.XChangeProperty(
+		conn.display,
+		conn.timeWindow,
+		C.XInternAtom(conn.display, propertyName, C.False),
+		C.XInternAtom(conn.display, propertyTypeName, C.False),
+		32,
+		C.PropModeAppend,
+		nil,
+		0,
+	)
+	if err := trap.untrap("XChangeProperty", true); err != nil {
+		return C.CurrentTime, err
+	}
+	// XSync waited for the event to be queued
+	var xEvent C.XEvent
+	if C.XCheckWindowEvent(conn.display, conn.timeWindow, C.PropertyChangeMask, &xEvent) == C.False {
+		return C.CurrentTime, fmt.Errorf("the X server didn't report the property change")
+	}
+	return (*C.XPropertyEvent)(unsafe.Pointer(&xEvent)).time, nil
+}
@@ -0,0 +1,131 @@
+package xlib
+
+//#include <X11/Xlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// ErrCloseTimeout Error returned by WaitForWindowClose when the window still exists after the timeout
+var ErrCloseTimeout = errors.New("the window wasn't closed before the timeout")
+
+// ErrDeleteNotSupported Error returned by DeleteWindow when the window doesn't take part in the WM_DELETE_WINDOW protocol
+var ErrDeleteNotSupported = errors.New("the window doesn't support the WM_DELETE_WINDOW protocol")
+
+// Milliseconds between every check of WaitForWindowClose
+const closePollInterval = 50
+
+/*
+CloseWindow Asks the window manager to close a window sending the client message "_NET_CLOSE_WINDOW", the window manager
+usually asks the client politely (WM_DELETE_WINDOW) so the application can show a confirmation of its own.
+
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) CloseWindow(window Window) (bool, error) {
+	conn.lock()
+	defer conn.unlock()
+	timestamp, err := conn.getServerTime()
+	if err != nil {
+		return false, err
+	}
+	return conn.SendClientMessage(window, "_NET_CLOSE_WINDOW", int64(timestamp), 2) // Source indication 2 (pager)
+}
+
+/*
+DeleteWindow Asks a client to close one of its windows sending it the client message "WM_PROTOCOLS" with
+"WM_DELETE_WINDOW" (ICCCM), bypassing the window manager.
+
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, ErrDeleteNotSupported if the window doesn't support the protocol or a *XError if the X
+    server reported an error
+*/
+func (conn *Conn) DeleteWindow(window Window) (bool, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
+	deleteWindowAtom := conn.internAtom("WM_DELETE_WINDOW")
+
+	var protocols *C.Atom
+	var count C.int
+	trap := trapErrors(display)
+	status := C.XGetWMProtocols(display, window, &protocols, &count)
+	if err := trap.untrap("XGetWMProtocols", false); err != nil {
+		return false, err
+	}
+	supported := false
+	if status != 0 && protocols != nil {
+		for _, protocol := range unsafe.Slice(protocols, int(count)) {
+			supported = supported || protocol == deleteWindowAtom
+		}
+		C.XFree(unsafe.Pointer(protocols))
+	}
+	if !supported {
+		return false, ErrDeleteNotSupported
+	}
+
+	timestamp, err := conn.getServerTime()
+	if err != nil {
+		return false, err
+	}
+	var xClientMessageEvent C.XClientMessageEvent
+	xClientMessageEvent._type = C.int(C.ClientMessage)
+	xClientMessageEvent.display = display
+	xClientMessageEvent.window = window
+	xClientMessageEvent.message_type = conn.internAtom("WM_PROTOCOLS")
+	xClientMessageEvent.format = C.int(32)
+	longs := (*[5]C.long)(unsafe.Pointer(&xClientMessageEvent.data))
+	longs[0] = C.long(deleteWindowAtom)
+	longs[1] = C.long(timestamp)
+
+	trap = trapErrors(display)
+	result := C.XSendEvent(display, window, C.False, C.NoEventMask, (*C.XEvent)(unsafe.Pointer(&xClientMessageEvent)))
+	if err := trap.untrap("XSendEvent", true); err != nil {
+		return false, err
+	}
+	return result != C.False, nil
+}
+
+/*
+KillClient Forces the X server to close the connection of the client that created a window, every window and resource
+of the client is destroyed. It should only be used when the client doesn't answer to CloseWindow or DeleteWindow.
+
+Returns:
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) KillClient(window Window) error {
+	conn.lock()
+	defer conn.unlock()
+	trap := trapErrors(conn.display)
+	C.XKillClient(conn.display, C.XID(window))
+	return trap.untrap("XKillClient", true)
+}
+
+/*
+WaitForWindowClose Waits until a window doesn't exist anymore.
+
+Parameters:
+  - window: Specifies the window to wait for.
+  - timeout: Specifies how long to wait.
+
+Returns:
+  - nil if the window was destroyed, ErrCloseTimeout otherwise
+*/
+func (conn *Conn) WaitForWindowClose(window Window, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := conn.GetWindowAttributes(window); IsWindowGone(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w (window %d)", ErrCloseTimeout, window)
+		}
+		time.Sleep(time.Millisecond * closePollInterval)
+	}
+}
@@ -0,0 +1,220 @@
+package xlib
+
+//#include <X11/Xlib.h>
+//
+//#define MAX_TRACKED_DISPLAYS 16
+//
+//typedef struct {
+//    Display *display;
+//    int error_code;
+//    int request_code;
+//    int minor_code;
+//    unsigned long resource_id;
+//    int connection_lost;
+//} display_errors;
+//
+//static display_errors tracked_displays[MAX_TRACKED_DISPLAYS];
+//static XErrorHandler previous_error_handler = NULL;
+//static XIOErrorHandler previous_io_error_handler = NULL;
+//
+//static display_errors *find_display(Display *display) {
+//    for (int i = 0; i < MAX_TRACKED_DISPLAYS; i++) {
+//        if (display != NULL && tracked_displays[i].display == display) {
+//            return &tracked_displays[i];
+//        }
+//    }
+//    return NULL;
+//}
+//
+//static int record_error(Display *display, XErrorEvent *event) {
+//    display_errors *errors = find_display(display);
+//    if (errors == NULL) {
+//        return previous_error_handler != NULL ? previous_error_handler(display, event) : 0;
+//    }
+//    if (errors->error_code == Success) {
+//        errors->error_code = event->error_code;
+//        errors->request_code = event->request_code;
+//        errors->minor_code = event->minor_code;
+//        errors->resource_id = event->resourceid;
+//    }
+//    return 0;
+//}
+//
+//static int record_io_error(Display *display) {
+//    display_errors *errors = find_display(display);
+//    if (errors == NULL) {
+//        return previous_io_error_handler != NULL ? previous_io_error_handler(display) : 0;
+//    }
+//    errors->connection_lost = 1;
+//    return 0;
+//}
+//
+//static void io_error_exit(Display *display, void *data) {
+//    // The process keeps running, every following call on the display fails
+//}
+//
+//static void install_error_handlers(void) {
+//    XErrorHandler current_error_handler = XSetErrorHandler(record_error);
+//    if (current_error_handler != record_error) {
+//        previous_error_handler = current_error_handler;
+//    }
+//    XIOErrorHandler current_io_error_handler = XSetIOErrorHandler(record_io_error);
+//    if (current_io_error_handler != record_io_error) {
+//        previous_io_error_handler = current_io_error_handler;
+//    }
+//}
+//
+//static int track_display(Display *display) {
+//    display_errors *errors = find_display(display);
+//    for (int i = 0; i < MAX_TRACKED_DISPLAYS && errors == NULL; i++) {
+//        if (tracked_displays[i].display == NULL) {
+//            errors = &tracked_displays[i];
+//        }
+//    }
+//    if (errors == NULL) {
+//        return 0;
+//    }
+//    errors->display = display;
+//    errors->error_code = Success;
+//    errors->connection_lost = 0;
+//    XSetIOErrorExitHandler(display, io_error_exit, NULL);
+//    install_error_handlers();
+//    return 1;
+//}
+//
+//static void untrack_display(Display *display) {
+//    display_errors *errors = find_display(display);
+//    if (errors != NULL) {
+//        errors->display = NULL;
+//    }
+//}
+//
+//static display_errors take_errors(Display *display) {
+//    display_errors result = { 0 };
+//    display_errors *errors = find_display(display);
+//    if (errors != NULL) {
+//        result = *errors;
+//        errors->error_code = Success;
+//    }
+//    return result;
+//}
+//
+//static void restore_errors(Display *display, display_errors previous) {
+//    display_errors *errors = find_display(display);
+//    if (errors != NULL && errors->error_code == Success) {
+//        errors->error_code = previous.error_code;
+//        errors->request_code = previous.request_code;
+//        errors->minor_code = previous.minor_code;
+//        errors->resource_id = previous.resource_id;
+//    }
+//}
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrConnectionLost The connection to the X server was lost, every following request on it fails
+var ErrConnectionLost = errors.New("the connection to the X server was lost")
+
+// XError Error reported by the X server for a request, it replaces the default Xlib error handler which prints the
+// error and exits the process.
+type XError struct {
+	Request     string // Name of the function that sent the failed request
+	Code        int    // Error code, e.g. BadWindow
+	RequestCode int    // Major opcode of the failed request
+	MinorCode   int    // Minor opcode of the failed request
+	ResourceID  uint64 // Resource (window, atom, pixmap...) the failed request was about
+	Text        string // Description of the error code given by XGetErrorText
+}
+
+func (xError *XError) Error() string {
+	return fmt.Sprintf(
+		"%s failed: %s (error code %d, request code %d.%d, resource id %d)",
+		xError.Request,
+		xError.Text,
+		xError.Code,
+		xError.RequestCode,
+		xError.MinorCode,
+		xError.ResourceID,
+	)
+}
+
+// IsBadWindow Whether the error is BadWindow, the window doesn't exist
+func (xError *XError) IsBadWindow() bool {
+	return xError.Code == C.BadWindow
+}
+
+// IsBadMatch Whether the error is BadMatch, the arguments don't match the state of the resource
+func (xError *XError) IsBadMatch() bool {
+	return xError.Code == C.BadMatch
+}
+
+// IsWindowGone Reports whether an error means that the window the request was about doesn't exist anymore
+func IsWindowGone(err error) bool {
+	var xError *XError
+	if !errors.As(err, &xError) {
+		return false
+	}
+	return xError.IsBadWindow() || xError.Code == C.BadDrawable
+}
+
+// Starts recording the errors of a connection to the X server, the display gets untracked with untrackDisplay
+func trackDisplay(display *Display) {
+	if C.track_display(display) == 0 {
+		fmt.Println("WARNING: too many connections to the X server, errors won't be recorded for: ", display)
+	}
+}
+
+// Stops recording the errors of a connection to the X server
+func untrackDisplay(display *Display) {
+	C.untrack_display(display)
+}
+
+// Errors recorded on a connection since trapErrors was called
+type errorTrap struct {
+	display  *Display
+	previous C.display_errors
+}
+
+// Starts collecting the errors of the requests sent on a connection, the trap must be closed with untrap
+func trapErrors(display *Display) errorTrap {
+	C.install_error_handlers() // The handlers get installed again in case someone else (e.g. GDK) replaced them
+	return errorTrap{display: display, previous: C.take_errors(display)}
+}
+
+/*
+untrap Returns the first error recorded since the trap was created.
+
+Parameters:
+  - request: Name of the function that sent the requests, used in the error message.
+  - sync: Whether to wait (XSync) for the replies of the requests, needed for requests without reply.
+
+Returns:
+  - A *XError, ErrConnectionLost or nil
+*/
+func (trap errorTrap) untrap(request string, sync bool) error {
+	if sync {
+		C.XSync(trap.display, C.False)
+	}
+	recorded := C.take_errors(trap.display)
+	C.restore_errors(trap.display, trap.previous)
+	if recorded.connection_lost != 0 {
+		return ErrConnectionLost
+	}
+	if recorded.error_code == C.Success {
+		return nil
+	}
+	text := make([]C.char, 256)
+	C.XGetErrorText(trap.display, recorded.error_code, unsafe.SliceData(text), C.int(len(text)))
+	return &XError{
+		Request:     request,
+		Code:        int(recorded.error_code),
+		RequestCode: int(recorded.request_code),
+		MinorCode:   int(recorded.minor_code),
+		ResourceID:  uint64(recorded.resource_id),
+		Text:        C.GoString(unsafe.SliceData(text)),
+	}
+}
@@ -0,0 +1,391 @@
+package xlib
+
+//#include <X11/Xlib.h>
+//#include <X11/extensions/Xrandr.h>
+//#include <poll.h>
+//
+//int wait_for_event(Display *display, int timeout) {
+//    struct pollfd fd = { .fd = ConnectionNumber(display), .events = POLLIN };
+//    return poll(&fd, 1, timeout);
+//}
+import "C"
+
+import (
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// Event Event emitted by a Subscription whenever something changes on the X server.
+type Event interface {
+	EventWindow() Window // Window the event is about
+}
+
+// WindowAddedEvent A new client window appeared on "_NET_CLIENT_LIST", with the properties it had at that moment.
+type WindowAddedEvent struct {
+	Window     Window
+	Class      []string // Strings of "WM_CLASS": instance and class. Empty if the window has no class
+	Title      string   // Title based on "_NET_WM_NAME" or "WM_NAME"
+	Desktop    int      // Desktop based on "_NET_WM_DESKTOP" or "_WIN_WORKSPACE"
+	HasDesktop bool     // Whether the window has any of the desktop properties
+}
+
+// WindowRemovedEvent A client window was removed from "_NET_CLIENT_LIST" or destroyed.
+type WindowRemovedEvent struct {
+	Window Window
+}
+
+// WindowTitleChangedEvent The property "_NET_WM_NAME" or "WM_NAME" of a client window changed.
+type WindowTitleChangedEvent struct {
+	Window Window
+	Title  string
+}
+
+// WindowDesktopChangedEvent The property "_NET_WM_DESKTOP" of a client window changed.
+type WindowDesktopChangedEvent struct {
+	Window  Window
+	Desktop int
+}
+
+// WindowGeometryChangedEvent A client window was moved or resized (ConfigureNotify), use Conn.GetWindowGeometry to
+// read its new geometry.
+type WindowGeometryChangedEvent struct {
+	Window Window
+}
+
+// ActiveWindowChangedEvent The property "_NET_ACTIVE_WINDOW" on the root window changed.
+type ActiveWindowChangedEvent struct {
+	Window Window
+}
+
+// ClientsSyncedEvent The WindowAddedEvent of every client window open when the subscription started was delivered,
+// from now on the events describe the whole client list. The event is about the root window.
+type ClientsSyncedEvent struct {
+	Root Window
+}
+
+// MonitorAddedEvent A monitor was connected or enabled, reported by XRandR. The event is about the root window.
+type MonitorAddedEvent struct {
+	Root    Window
+	Monitor Monitor
+}
+
+// MonitorRemovedEvent A monitor was disconnected or disabled, reported by XRandR. The event is about the root window.
+type MonitorRemovedEvent struct {
+	Root    Window
+	Monitor Monitor
+}
+
+// MonitorChangedEvent The geometry of a monitor or whether it's the primary one changed, reported by XRandR. The event
+// is about the root window.
+type MonitorChangedEvent struct {
+	Root    Window
+	Monitor Monitor
+}
+
+func (event WindowAddedEvent) EventWindow() Window           { return event.Window }
+func (event WindowRemovedEvent) EventWindow() Window         { return event.Window }
+func (event WindowTitleChangedEvent) EventWindow() Window    { return event.Window }
+func (event WindowDesktopChangedEvent) EventWindow() Window  { return event.Window }
+func (event WindowGeometryChangedEvent) EventWindow() Window { return event.Window }
+func (event ActiveWindowChangedEvent) EventWindow() Window   { return event.Window }
+func (event ClientsSyncedEvent) EventWindow() Window         { return event.Root }
+func (event MonitorAddedEvent) EventWindow() Window          { return event.Root }
+func (event MonitorRemovedEvent) EventWindow() Window        { return event.Root }
+func (event MonitorChangedEvent) EventWindow() Window        { return event.Root }
+
+// Subscription Watches the root window and every client window using its own connection to the X server, the
+// connection is only used by the goroutine of the subscription.
+type Subscription struct {
+	conn         *Conn
+	root         Window
+	atoms        map[string]C.Atom
+	clients      map[Window]bool
+	activeWindow Window
+	monitors     []Monitor // Monitors already reported, in the order of XRRGetMonitors
+	randrEvent   C.int     // First event code of XRandR, -1 if the extension is not available
+	events       chan Event
+	done         chan struct{}
+}
+
+const (
+	// Amount of events that can be queued before the subscription blocks
+	subscriptionBufferSize = 64
+
+	// Milliseconds the subscription waits for new events before checking if it was closed
+	subscriptionPollTimeout = 100
+)
+
+/*
+Subscribe Opens a new connection to the X server and starts watching for changes.
+
+PropertyNotify and SubstructureNotify events are selected on the root window and PropertyNotify and StructureNotify
+events on every client window. When XRandR is available the changes of the screen, CRTCs and outputs are selected too,
+to report the monitors added and removed. The current state is delivered first: a MonitorAddedEvent for every
+monitor, a WindowAddedEvent for every client window, a ClientsSyncedEvent and an ActiveWindowChangedEvent for the
+current active window.
+
+Returns:
+  - A Subscription whose channel receives the events
+  - Possible error or nil
+*/
+func Subscribe() (*Subscription, error) {
+	conn, err := OpenConn()
+	if err != nil {
+		return nil, err
+	}
+	display := conn.display
+	subscription := &Subscription{
+		conn:       conn,
+		root:       conn.root,
+		atoms:      map[string]C.Atom{},
+		clients:    map[Window]bool{},
+		randrEvent: -1,
+		events:     make(chan Event, subscriptionBufferSize),
+		done:       make(chan struct{}),
+	}
+	for _, atomName := range []string{
+		"_NET_CLIENT_LIST",
+		"_WIN_CLIENT_LIST",
+		"_NET_ACTIVE_WINDOW",
+		"_NET_WM_NAME",
+		"WM_NAME",
+		"_NET_WM_DESKTOP",
+		"_WIN_WORKSPACE",
+	} {
+		name := C.CString(atomName)
+		subscription.atoms[atomName] = C.XInternAtom(display, name, C.False)
+		C.XFree(unsafe.Pointer(name))
+	}
+	C.XSelectInput(display, subscription.root, C.PropertyChangeMask|C.SubstructureNotifyMask)
+	var randrEvent, randrError C.int
+	if C.XRRQueryExtension(display, &randrEvent, &randrError) != C.False {
+		subscription.randrEvent = randrEvent
+		C.XRRSelectInput(
+			display,
+			subscription.root,
+			C.RRScreenChangeNotifyMask|C.RRCrtcChangeNotifyMask|C.RROutputChangeNotifyMask,
+		)
+	}
+
+	go subscription.run()
+	return subscription, nil
+}
+
+// Events Channel where the events are delivered, it gets closed when the subscription is closed.
+func (subscription *Subscription) Events() <-chan Event {
+	return subscription.events
+}
+
+// Close Stops watching for changes and closes the connection to the X server.
+func (subscription *Subscription) Close() {
+	select {
+	case <-subscription.done:
+	default:
+		close(subscription.done)
+	}
+}
+
+// Main loop of the subscription, it owns the connection to the X server until the subscription is closed
+func (subscription *Subscription) run() {
+	defer func() {
+		subscription.conn.Close()
+		close(subscription.events)
+	}()
+
+	// Initial state
+	pending := subscription.updateMonitors()
+	pending = append(pending, subscription.updateClients()...)
+	pending = append(pending, ClientsSyncedEvent{Root: subscription.root})
+	if result, activeWindow := subscription.getActiveWindow(); result {
+		subscription.activeWindow = activeWindow
+		pending = append(pending, ActiveWindowChangedEvent{Window: activeWindow})
+	}
+	C.XFlush(subscription.conn.display)
+
+	for {
+		for _, event := range pending {
+			select {
+			case subscription.events <- event:
+			case <-subscription.done:
+				return
+			}
+		}
+		pending = nil
+
+		select {
+		case <-subscription.done:
+			return
+		default:
+		}
+
+		if C.XPending(subscription.conn.display) == 0 {
+			C.wait_for_event(subscription.conn.display, subscriptionPollTimeout)
+			continue
+		}
+		var xEvent C.XEvent
+		C.XNextEvent(subscription.conn.display, &xEvent)
+		pending = subscription.handleEvent(&xEvent)
+	}
+}
+
+// Translates a XEvent to the events of the subscription
+func (subscription *Subscription) handleEvent(xEvent *C.XEvent) []Event {
+	eventType := *(*C.int)(unsafe.Pointer(xEvent))
+	if subscription.randrEvent >= 0 && (eventType == subscription.randrEvent+C.RRScreenChangeNotify ||
+		eventType == subscription.randrEvent+C.RRNotify) {
+		C.XRRUpdateConfiguration(xEvent)
+		return subscription.updateMonitors()
+	}
+	switch eventType {
+	case C.PropertyNotify:
+		propertyEvent := (*C.XPropertyEvent)(unsafe.Pointer(xEvent))
+		window := Window(propertyEvent.window)
+		if window == subscription.root {
+			switch propertyEvent.atom {
+			case subscription.atoms["_NET_CLIENT_LIST"], subscription.atoms["_WIN_CLIENT_LIST"]:
+				return subscription.updateClients()
+			case subscription.atoms["_NET_ACTIVE_WINDOW"]:
+				result, activeWindow := subscription.getActiveWindow()
+				if !result || activeWindow == subscription.activeWindow {
+					return nil
+				}
+				subscription.activeWindow = activeWindow
+				return []Event{ActiveWindowChangedEvent{Window: activeWindow}}
+			}
+			return nil
+		}
+		if !subscription.clients[window] {
+			return nil
+		}
+		switch propertyEvent.atom {
+		case subscription.atoms["_NET_WM_NAME"], subscription.atoms["WM_NAME"]:
+			return []Event{WindowTitleChangedEvent{Window: window, Title: subscription.getTitle(window)}}
+		case subscription.atoms["_NET_WM_DESKTOP"], subscription.atoms["_WIN_WORKSPACE"]:
+			desktop, _ := subscription.getDesktop(window)
+			return []Event{WindowDesktopChangedEvent{Window: window, Desktop: desktop}}
+		}
+	case C.ConfigureNotify:
+		window := Window((*C.XConfigureEvent)(unsafe.Pointer(xEvent)).window)
+		if subscription.clients[window] {
+			return []Event{WindowGeometryChangedEvent{Window: window}}
+		}
+	case C.DestroyNotify:
+		window := Window((*C.XDestroyWindowEvent)(unsafe.Pointer(xEvent)).window)
+		if subscription.clients[window] {
+			delete(subscription.clients, window)
+			return []Event{WindowRemovedEvent{Window: window}}
+		}
+	}
+	return nil
+}
+
+// Reads the client list of the root window, selects input on new clients and returns the differences as events
+func (subscription *Subscription) updateClients() []Event {
+	var events []Event
+	clientList, err := subscription.conn.GetWindowProperty(subscription.root, "_NET_CLIENT_LIST")
+	if clientList == nil || err != nil || len(clientList.GetLong()) == 0 {
+		// GNOME Spec property "_WIN_CLIENT_LIST"
+		clientList, _ = subscription.conn.GetWindowProperty(subscription.root, "_WIN_CLIENT_LIST")
+	}
+	currentClients := map[Window]bool{}
+	if clientList != nil {
+		for _, windowId := range clientList.GetLong() {
+			window := Window(windowId)
+			currentClients[window] = true
+			if subscription.clients[window] {
+				continue
+			}
+			trap := trapErrors(subscription.conn.display)
+			C.XSelectInput(subscription.conn.display, window, C.PropertyChangeMask|C.StructureNotifyMask)
+			if err := trap.untrap("XSelectInput", true); IsWindowGone(err) {
+				// The window was destroyed before it could be watched
+				delete(currentClients, window)
+				continue
+			}
+			subscription.clients[window] = true
+			desktop, hasDesktop := subscription.getDesktop(window)
+			events = append(events, WindowAddedEvent{
+				Window:     window,
+				Class:      subscription.getClass(window),
+				Title:      subscription.getTitle(window),
+				Desktop:    desktop,
+				HasDesktop: hasDesktop,
+			})
+		}
+	}
+	for window := range subscription.clients {
+		if !currentClients[window] {
+			delete(subscription.clients, window)
+			events = append(events, WindowRemovedEvent{Window: window})
+		}
+	}
+	return events
+}
+
+// Reads the monitors of the screen and returns the differences with the ones already reported as events, the
+// monitors are matched by name
+func (subscription *Subscription) updateMonitors() []Event {
+	var events []Event
+	currentMonitors := subscription.conn.GetMonitors()
+	for _, monitor := range currentMonitors {
+		index := slices.IndexFunc(subscription.monitors, func(m Monitor) bool { return m.Name == monitor.Name })
+		switch {
+		case index < 0:
+			events = append(events, MonitorAddedEvent{Root: subscription.root, Monitor: monitor})
+		case subscription.monitors[index] != monitor:
+			events = append(events, MonitorChangedEvent{Root: subscription.root, Monitor: monitor})
+		}
+	}
+	for _, monitor := range subscription.monitors {
+		if !slices.ContainsFunc(currentMonitors, func(m Monitor) bool { return m.Name == monitor.Name }) {
+			events = append(events, MonitorRemovedEvent{Root: subscription.root, Monitor: monitor})
+		}
+	}
+	subscription.monitors = currentMonitors
+	return events
+}
+
+// Gets the current active window using the connection of the subscription
+func (subscription *Subscription) getActiveWindow() (bool, Window) {
+	activeWindow, err := subscription.conn.GetWindowProperty(subscription.root, "_NET_ACTIVE_WINDOW")
+	if activeWindow == nil || err != nil || len(activeWindow.GetLong()) == 0 {
+		return false, Window(0)
+	}
+	return true, Window(activeWindow.GetLong()[0])
+}
+
+// Gets the title of a window based on the property "_NET_WM_NAME" or "WM_NAME"
+func (subscription *Subscription) getTitle(window Window) string {
+	title, err := subscription.conn.GetWindowProperty(window, "_NET_WM_NAME")
+	if title == nil || err != nil || len(title.GetString()) == 0 {
+		title, err = subscription.conn.GetWindowProperty(window, "WM_NAME")
+		if title == nil || err != nil {
+			return ""
+		}
+	}
+	return strings.Join(title.GetString(), " ")
+}
+
+// Gets the desktop of a window based on the property "_NET_WM_DESKTOP" or "_WIN_WORKSPACE", -1 and false if the
+// window has none of them
+func (subscription *Subscription) getDesktop(window Window) (int, bool) {
+	desktop, err := subscription.conn.GetWindowProperty(window, "_NET_WM_DESKTOP")
+	if desktop == nil || err != nil || len(desktop.GetLong()) == 0 {
+		// GNOME Spec property "_WIN_WORKSPACE"
+		desktop, err = subscription.conn.GetWindowProperty(window, "_WIN_WORKSPACE")
+		if desktop == nil || err != nil || len(desktop.GetLong()) == 0 {
+			return -1, false
+		}
+	}
+	return int(desktop.GetLong()[0]), true
+}
+
+// Gets the strings of the property "WM_CLASS" of a window
+func (subscription *Subscription) getClass(window Window) []string {
+	class, err := subscription.conn.GetWindowProperty(window, "WM_CLASS")
+	if class == nil || err != nil {
+		return nil
+	}
+	return class.GetString()
+}
@@ -0,0 +1,195 @@
+package xlib
+
+//#include <X11/Xlib.h>
+import "C"
+
+import "fmt"
+
+// Rectangle Position and size of a window, the position is relative to the root window.
+type Rectangle struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// String Representation of the rectangle like a X geometry: WIDTHxHEIGHT+X+Y.
+func (rectangle Rectangle) String() string {
+	return fmt.Sprintf("%dx%d%+d%+d", rectangle.Width, rectangle.Height, rectangle.X, rectangle.Y)
+}
+
+// Contains Whether a point is inside a rectangle.
+func (rectangle Rectangle) Contains(x int, y int) bool {
+	return x >= rectangle.X && x < rectangle.X+rectangle.Width && y >= rectangle.Y && y < rectangle.Y+rectangle.Height
+}
+
+// Intersection Rectangle shared by two rectangles, its width and height are 0 if they don't overlap.
+func (rectangle Rectangle) Intersection(other Rectangle) Rectangle {
+	x := max(rectangle.X, other.X)
+	y := max(rectangle.Y, other.Y)
+	width := min(rectangle.X+rectangle.Width, other.X+other.Width) - x
+	height := min(rectangle.Y+rectangle.Height, other.Y+other.Height) - y
+	if width <= 0 || height <= 0 {
+		return Rectangle{X: x, Y: y}
+	}
+	return Rectangle{X: x, Y: y, Width: width, Height: height}
+}
+
+// ParseRectangle Parses a X geometry with the format WIDTHxHEIGHT+X+Y (X and Y can be negative) into a Rectangle.
+func ParseRectangle(geometry string) (Rectangle, error) {
+	var rectangle Rectangle
+	_, err := fmt.Sscanf(geometry, "%dx%d%d%d", &rectangle.Width, &rectangle.Height, &rectangle.X, &rectangle.Y)
+	if err != nil || rectangle.Width <= 0 || rectangle.Height <= 0 {
+		return rectangle, fmt.Errorf("the geometry \"%s\" is not valid, expected WIDTHxHEIGHT+X+Y", geometry)
+	}
+	return rectangle, nil
+}
+
+// FrameExtents Size of the decorations added by the window manager, based on the property "_NET_FRAME_EXTENTS".
+type FrameExtents struct {
+	Left   int
+	Right  int
+	Top    int
+	Bottom int
+}
+
+// WindowGeometry Absolute geometry of a window and of its frame.
+type WindowGeometry struct {
+	Client  Rectangle    // Geometry of the client window, without decorations
+	Frame   Rectangle    // Geometry of the window including the decorations of the window manager
+	Extents FrameExtents // Size of the decorations
+}
+
+// Flags of "_NET_MOVERESIZE_WINDOW": gravity, x, y, width and height present, source indication 2 (pager)
+const moveResizeFlags = C.StaticGravity | 1<<8 | 1<<9 | 1<<10 | 1<<11 | 2<<12
+
+/*
+GetWindowGeometry Gets the absolute geometry of a window and of its frame, based on XGetWindowAttributes,
+XTranslateCoordinates and the property "_NET_FRAME_EXTENTS".
+
+Returns:
+  - A WindowGeometry with the geometry of the window
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) GetWindowGeometry(window Window) (*WindowGeometry, error) {
+	conn.lock()
+	defer conn.unlock()
+	windowAttributes, err := conn.GetWindowAttributes(window)
+	if windowAttributes == nil || err != nil {
+		return nil, err
+	}
+
+	var x, y C.int
+	var child Window
+	trap := trapErrors(conn.display)
+	result := C.XTranslateCoordinates(conn.display, window, windowAttributes.Root, 0, 0, &x, &y, &child)
+	if err := trap.untrap("XTranslateCoordinates", false); err != nil {
+		return nil, err
+	}
+	if result == C.False {
+		return nil, fmt.Errorf("the window %d is not on the same screen than its root window", window)
+	}
+
+	geometry := &WindowGeometry{
+		Client: Rectangle{X: int(x), Y: int(y), Width: windowAttributes.Width, Height: windowAttributes.Height},
+	}
+	if frameExtents, _ := conn.GetWindowProperty(window, "_NET_FRAME_EXTENTS"); frameExtents != nil &&
+		len(frameExtents.GetLong()) == 4 {
+		extents := frameExtents.GetLong()
+		geometry.Extents = FrameExtents{
+			Left:   int(extents[0]),
+			Right:  int(extents[1]),
+			Top:    int(extents[2]),
+			Bottom: int(extents[3]),
+		}
+	}
+	geometry.Frame = Rectangle{
+		X:      geometry.Client.X - geometry.Extents.Left,
+		Y:      geometry.Client.Y - geometry.Extents.Top,
+		Width:  geometry.Client.Width + geometry.Extents.Left + geometry.Extents.Right,
+		Height: geometry.Client.Height + geometry.Extents.Top + geometry.Extents.Bottom,
+	}
+	return geometry, nil
+}
+
+/*
+MoveResizeWindow Moves and resizes a window sending the client message "_NET_MOVERESIZE_WINDOW", if the window manager
+doesn't support it the window is configured with XMoveResizeWindow.
+
+Parameters:
+  - window: Specifies the window to move and resize.
+  - client: Specifies the new absolute geometry of the client window, without decorations.
+
+Returns:
+  - A boolean indicating if the requests were sent
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) MoveResizeWindow(window Window, client Rectangle) (bool, error) {
+	if client.Width <= 0 || client.Height <= 0 {
+		return false, fmt.Errorf("the size %dx%d is not valid", client.Width, client.Height)
+	}
+	conn.lock()
+	defer conn.unlock()
+	if conn.isSupported("_NET_MOVERESIZE_WINDOW") {
+		sent, err := conn.SendClientMessage(
+			window,
+			"_NET_MOVERESIZE_WINDOW",
+			moveResizeFlags,
+			int64(client.X),
+			int64(client.Y),
+			int64(client.Width),
+			int64(client.Height),
+		)
+		if sent || IsWindowGone(err) {
+			return sent, err
+		}
+	}
+
+	// The window manager places the frame at the requested position (NorthWestGravity)
+	var extents FrameExtents
+	if geometry, err := conn.GetWindowGeometry(window); geometry != nil && err == nil {
+		extents = geometry.Extents
+	}
+	trap := trapErrors(conn.display)
+	C.XMoveResizeWindow(
+		conn.display,
+		window,
+		C.int(client.X-extents.Left),
+		C.int(client.Y-extents.Top),
+		C.uint(client.Width),
+		C.uint(client.Height),
+	)
+	if err := trap.untrap("XMoveResizeWindow", true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MoveResizeFrame Moves and resizes a window given the new absolute geometry of its frame, see MoveResizeWindow.
+func (conn *Conn) MoveResizeFrame(window Window, frame Rectangle) (bool, error) {
+	geometry, err := conn.GetWindowGeometry(window)
+	if geometry == nil || err != nil {
+		return false, err
+	}
+	return conn.MoveResizeWindow(window, Rectangle{
+		X:      frame.X + geometry.Extents.Left,
+		Y:      frame.Y + geometry.Extents.Top,
+		Width:  frame.Width - geometry.Extents.Left - geometry.Extents.Right,
+		Height: frame.Height - geometry.Extents.Top - geometry.Extents.Bottom,
+	})
+}
+
+// Whether the window manager supports a hint based on the property "_NET_SUPPORTED". The display must be locked.
+func (conn *Conn) isSupported(hint string) bool {
+	supported, err := conn.GetWindowProperty(conn.root, "_NET_SUPPORTED")
+	if supported == nil || err != nil {
+		return false
+	}
+	atom := int64(conn.internAtom(hint))
+	for _, supportedAtom := range supported.GetLong() {
+		if supportedAtom == atom {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package xlib
+
+//#cgo pkg-config: xscrnsaver
+//#include <X11/Xlib.h>
+//#include <X11/extensions/scrnsaver.h>
+import "C"
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// ErrIdleTimeNotSupported Error returned by GetIdleTime when the X server doesn't have the extension MIT-SCREEN-SAVER
+var ErrIdleTimeNotSupported = errors.New("the X server doesn't support the extension MIT-SCREEN-SAVER")
+
+/*
+GetIdleTime Gets the time since the last keyboard or mouse input received by the X server, using the extension
+MIT-SCREEN-SAVER (XScreenSaverQueryInfo).
+
+Returns:
+  - The time since the last input
+  - Possible error or nil, ErrIdleTimeNotSupported if the extension is not available
+*/
+func (conn *Conn) GetIdleTime() (time.Duration, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
+
+	var eventBase, errorBase C.int
+	if C.XScreenSaverQueryExtension(display, &eventBase, &errorBase) == C.False {
+		return 0, ErrIdleTimeNotSupported
+	}
+	info := C.XScreenSaverAllocInfo()
+	if info == nil {
+		return 0, errors.New("an error occurred allocating the screen saver info")
+	}
+	defer C.XFree(unsafe.Pointer(info))
+
+	trap := trapErrors(display)
+	C.XScreenSaverQueryInfo(display, C.Drawable(conn.root), info)
+	if err := trap.untrap("XScreenSaverQueryInfo", true); err != nil {
+		return 0, err
+	}
+	return time.Duration(info.idle) * time.Millisecond, nil
+}
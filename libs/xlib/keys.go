@@ -0,0 +1,363 @@
+package xlib
+
+//#include <X11/Xlib.h>
+//#include <X11/XKBlib.h>
+//
+//int wait_for_event(Display *display, int timeout);
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+var (
+	// ErrKeyAlreadyGrabbed The key combination is already grabbed by another client of the X server
+	ErrKeyAlreadyGrabbed = errors.New("key combination already grabbed by another client")
+	// ErrInvalidKeyCombination The key combination can't be grabbed, it must have exactly one key besides the modifiers
+	ErrInvalidKeyCombination = errors.New("key combination must have exactly one key besides the modifiers")
+)
+
+// Keysym of the key Num_Lock
+const keysymNumLock = 0xff7f
+
+// KeyGrabber Registers key combinations with XGrabKey on the root window using its own connection to the X server.
+type KeyGrabber struct {
+	conn        *Conn
+	root        Window
+	numLockMask C.uint
+	grabs       []keyGrab
+	pressedKey  C.uint
+	holding     int // Index of the held key combination whose modifiers are still pressed, -1 if none
+	requests    chan func()
+	events      chan GrabbedKeyEvent
+	done        chan struct{}
+}
+
+// GrabbedKeyEvent Event of a grabbed key combination.
+type GrabbedKeyEvent struct {
+	Index    int  // Index of the key combination, as passed to GrabKeys
+	Released bool // Whether the modifiers of a held combination were released, false when the combination was pressed
+}
+
+// Key combination grabbed on the root window
+type keyGrab struct {
+	keycode   C.uint
+	modifiers C.uint
+	hold      bool
+}
+
+// NewKeyGrabber Opens a new connection to the X server to grab key combinations on the root window
+func NewKeyGrabber() (*KeyGrabber, error) {
+	conn, err := OpenConn()
+	if err != nil {
+		return nil, err
+	}
+	display := conn.display
+	// Auto-repeat only sends KeyPress events, so a held key can be told apart from a new keystroke
+	C.XkbSetDetectableAutoRepeat(display, C.True, nil)
+
+	grabber := &KeyGrabber{
+		conn:     conn,
+		root:     conn.root,
+		holding:  -1,
+		requests: make(chan func()),
+		events:   make(chan GrabbedKeyEvent, subscriptionBufferSize),
+		done:     make(chan struct{}),
+	}
+	if keycode := C.XKeysymToKeycode(display, keysymNumLock); keycode != 0 {
+		grabber.numLockMask = grabber.modifierMask(C.uint(keycode))
+	}
+	go grabber.run()
+	return grabber, nil
+}
+
+/*
+Events Channel that receives an event every time a grabbed key combination is pressed and, for the held combinations,
+when their modifiers are released.
+*/
+func (grabber *KeyGrabber) Events() <-chan GrabbedKeyEvent {
+	return grabber.events
+}
+
+/*
+GrabKeys Replaces the grabbed key combinations with new ones.
+
+Every combination is grabbed with all the variants of the NumLock and CapsLock modifiers, so it works no matter
+their state.
+
+When a held combination is pressed the whole keyboard is grabbed until any of its modifiers is released, so the
+key can be tapped again while the modifiers are held (like Alt+Tab) and the release gets reported.
+
+Parameters:
+  - combinations: Key combinations to grab, every combination is a slice of keysyms.
+  - hold: Whether every combination is held, it can be shorter than combinations or nil.
+
+Returns:
+  - A slice with an error (or nil if it was grabbed) for every combination. ErrKeyAlreadyGrabbed is returned when
+    another client already grabbed the combination.
+*/
+func (grabber *KeyGrabber) GrabKeys(combinations [][]uint, hold []bool) []error {
+	errs := make([]error, len(combinations))
+	grabber.request(func() {
+		grabber.ungrabAll()
+		grabber.grabs = make([]keyGrab, len(combinations))
+		for index, keysyms := range combinations {
+			grab, err := grabber.keyGrabFromKeysyms(keysyms)
+			grab.hold = index < len(hold) && hold[index]
+			if err == nil {
+				err = grabber.grab(grab)
+			}
+			if err != nil {
+				errs[index] = err
+				continue
+			}
+			grabber.grabs[index] = grab
+		}
+	})
+	return errs
+}
+
+// IdleTime Time since the last keyboard or mouse input received by the X server, see Conn.GetIdleTime.
+func (grabber *KeyGrabber) IdleTime() (time.Duration, error) {
+	idle, err := time.Duration(0), ErrIdleTimeNotSupported
+	grabber.request(func() { idle, err = grabber.conn.GetIdleTime() })
+	return idle, err
+}
+
+// UngrabAll Releases all the grabbed key combinations.
+func (grabber *KeyGrabber) UngrabAll() {
+	grabber.request(grabber.ungrabAll)
+}
+
+// Close Releases all the grabbed key combinations and closes the connection to the X server.
+func (grabber *KeyGrabber) Close() {
+	select {
+	case <-grabber.done:
+	default:
+		close(grabber.done)
+	}
+}
+
+// Executes a function inside the main loop of the grabber, which owns the connection to the X server
+func (grabber *KeyGrabber) request(function func()) {
+	finished := make(chan struct{})
+	select {
+	case grabber.requests <- func() { function(); close(finished) }:
+		<-finished
+	case <-grabber.done:
+	}
+}
+
+// Main loop of the grabber
+func (grabber *KeyGrabber) run() {
+	defer func() {
+		grabber.ungrabAll()
+		grabber.conn.Close()
+		close(grabber.events)
+	}()
+
+	for {
+		select {
+		case <-grabber.done:
+			return
+		case function := <-grabber.requests:
+			function()
+			continue
+		default:
+		}
+
+		if C.XPending(grabber.conn.display) == 0 {
+			C.wait_for_event(grabber.conn.display, subscriptionPollTimeout)
+			continue
+		}
+		var xEvent C.XEvent
+		C.XNextEvent(grabber.conn.display, &xEvent)
+		eventType := *(*C.int)(unsafe.Pointer(&xEvent))
+		if eventType != C.KeyPress && eventType != C.KeyRelease {
+			continue
+		}
+		keyEvent := (*C.XKeyEvent)(unsafe.Pointer(&xEvent))
+		if eventType == C.KeyRelease {
+			if keyEvent.keycode == grabber.pressedKey {
+				grabber.pressedKey = 0
+			}
+			if grabber.holding >= 0 &&
+				grabber.modifierMask(C.uint(keyEvent.keycode))&grabber.grabs[grabber.holding].modifiers != 0 {
+				grabber.stopHolding()
+			}
+			continue
+		}
+		if keyEvent.keycode == grabber.pressedKey { // Auto-repeat of the combination already reported
+			continue
+		}
+		modifiers := keyEvent.state & grabber.relevantModifiers()
+		for index, grab := range grabber.grabs {
+			if grab.keycode == keyEvent.keycode && grab.modifiers == modifiers {
+				grabber.pressedKey = keyEvent.keycode
+				if !grabber.send(GrabbedKeyEvent{Index: index}) {
+					return
+				}
+				if grab.hold && grabber.holding < 0 && !grabber.startHolding(index) {
+					// The modifiers were already released, the combination is released right away
+					if !grabber.send(GrabbedKeyEvent{Index: index, Released: true}) {
+						return
+					}
+				}
+				break
+			}
+		}
+	}
+}
+
+// Sends an event to the channel of events, it returns false if the grabber was closed
+func (grabber *KeyGrabber) send(event GrabbedKeyEvent) bool {
+	select {
+	case grabber.events <- event:
+		return true
+	case <-grabber.done:
+		return false
+	}
+}
+
+// Grabs the whole keyboard until the modifiers of a held key combination are released, it returns false if the
+// keyboard couldn't be grabbed or the modifiers aren't pressed anymore
+func (grabber *KeyGrabber) startHolding(index int) bool {
+	display := grabber.conn.display
+	modifiers := grabber.grabs[index].modifiers
+	if modifiers == 0 {
+		return false
+	}
+	result := C.XGrabKeyboard(display, grabber.root, C.False, C.GrabModeAsync, C.GrabModeAsync, C.CurrentTime)
+	if result != C.GrabSuccess {
+		return false
+	}
+	// The modifiers could have been released before the keyboard was grabbed
+	var root, child Window
+	var rootX, rootY, winX, winY C.int
+	var mask C.uint
+	C.XQueryPointer(display, grabber.root, &root, &child, &rootX, &rootY, &winX, &winY, &mask)
+	if mask&modifiers != modifiers {
+		C.XUngrabKeyboard(display, C.CurrentTime)
+		return false
+	}
+	grabber.holding = index
+	return true
+}
+
+// Releases the keyboard grabbed by a held key combination and reports the release
+func (grabber *KeyGrabber) stopHolding() {
+	if grabber.holding < 0 {
+		return
+	}
+	C.XUngrabKeyboard(grabber.conn.display, C.CurrentTime)
+	C.XFlush(grabber.conn.display)
+	event := GrabbedKeyEvent{Index: grabber.holding, Released: true}
+	grabber.holding = -1
+	select {
+	case grabber.events <- event:
+	default: // Nobody is reading the events, the grabber is being closed
+	}
+}
+
+// Modifiers taken into account when matching a key combination, NumLock and CapsLock are ignored
+func (grabber *KeyGrabber) relevantModifiers() C.uint {
+	return (C.ShiftMask | C.ControlMask | C.Mod1Mask | C.Mod2Mask | C.Mod3Mask | C.Mod4Mask | C.Mod5Mask) &^
+		grabber.numLockMask
+}
+
+// Converts a slice of keysyms to the keycode and modifiers to grab
+func (grabber *KeyGrabber) keyGrabFromKeysyms(keysyms []uint) (keyGrab, error) {
+	var grab keyGrab
+	for _, keysym := range keysyms {
+		keycode := C.uint(C.XKeysymToKeycode(grabber.conn.display, C.KeySym(keysym)))
+		if keycode == 0 {
+			return grab, fmt.Errorf("the keysym %#x is not available on the keyboard", keysym)
+		}
+		if mask := grabber.modifierMask(keycode); mask != 0 {
+			grab.modifiers |= mask
+			continue
+		}
+		if grab.keycode != 0 {
+			return grab, ErrInvalidKeyCombination
+		}
+		grab.keycode = keycode
+	}
+	if grab.keycode == 0 {
+		return grab, ErrInvalidKeyCombination
+	}
+	return grab, nil
+}
+
+// Returns the modifier mask associated to a keycode based on the modifier mapping, 0 if it is not a modifier
+func (grabber *KeyGrabber) modifierMask(keycode C.uint) C.uint {
+	modifierMap := C.XGetModifierMapping(grabber.conn.display)
+	if modifierMap == nil {
+		return 0
+	}
+	defer C.XFreeModifiermap(modifierMap)
+	keysPerModifier := int(modifierMap.max_keypermod)
+	keycodes := unsafe.Slice(modifierMap.modifiermap, 8*keysPerModifier)
+	for modifier := range 8 {
+		for _, modifierKeycode := range keycodes[modifier*keysPerModifier : (modifier+1)*keysPerModifier] {
+			if modifierKeycode != 0 && C.uint(modifierKeycode) == keycode {
+				return C.uint(1) << modifier
+			}
+		}
+	}
+	return 0
+}
+
+// Grabs a key combination with all the variants of the NumLock and CapsLock modifiers
+func (grabber *KeyGrabber) grab(grab keyGrab) error {
+	trap := trapErrors(grabber.conn.display)
+	for _, variant := range grabber.lockVariants() {
+		C.XGrabKey(
+			grabber.conn.display,
+			C.int(grab.keycode),
+			grab.modifiers|variant,
+			grabber.root,
+			C.False,
+			C.GrabModeAsync,
+			C.GrabModeAsync,
+		)
+	}
+	err := trap.untrap("XGrabKey", true)
+	if err == nil {
+		return nil
+	}
+	grabber.ungrab(grab)
+	var xError *XError
+	if errors.As(err, &xError) && xError.Code == C.BadAccess {
+		return fmt.Errorf("%w: %w", ErrKeyAlreadyGrabbed, err)
+	}
+	return err
+}
+
+// Releases a key combination with all the variants of the NumLock and CapsLock modifiers
+func (grabber *KeyGrabber) ungrab(grab keyGrab) {
+	trap := trapErrors(grabber.conn.display)
+	for _, variant := range grabber.lockVariants() {
+		C.XUngrabKey(grabber.conn.display, C.int(grab.keycode), grab.modifiers|variant, grabber.root)
+	}
+	_ = trap.untrap("XUngrabKey", true)
+}
+
+// Releases all the grabbed key combinations
+func (grabber *KeyGrabber) ungrabAll() {
+	grabber.stopHolding()
+	for _, grab := range grabber.grabs {
+		if grab.keycode != 0 {
+			grabber.ungrab(grab)
+		}
+	}
+	grabber.grabs = nil
+	grabber.pressedKey = 0
+}
+
+// Combinations of NumLock and CapsLock modifiers
+func (grabber *KeyGrabber) lockVariants() []C.uint {
+	return []C.uint{0, C.LockMask, grabber.numLockMask, C.LockMask | grabber.numLockMask}
+}
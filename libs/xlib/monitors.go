@@ -0,0 +1,59 @@
+package xlib
+
+// Monitor Monitor of the screen reported by XRandR.
+type Monitor struct {
+	Name     string    // Name of the monitor, e.g. "eDP-1" or "HDMI-1"
+	Geometry Rectangle // Position and size of the monitor, relative to the root window
+	Primary  bool      // Whether it's the primary monitor
+}
+
+/*
+GetMonitorAt Gets the monitor that contains a point, the nearest one if no monitor contains it.
+
+Parameters:
+  - monitors: Monitors returned by GetMonitors.
+  - x, y: Coordinates of the point, relative to the root window.
+
+Returns:
+  - The index of the monitor, -1 if monitors is empty
+*/
+func GetMonitorAt(monitors []Monitor, x int, y int) int {
+	nearest, nearestDistance := -1, 0
+	for index, monitor := range monitors {
+		if monitor.Geometry.Contains(x, y) {
+			return index
+		}
+		// Distance from the point to the closest edge of the monitor
+		distanceX := max(monitor.Geometry.X-x, 0, x-(monitor.Geometry.X+monitor.Geometry.Width-1))
+		distanceY := max(monitor.Geometry.Y-y, 0, y-(monitor.Geometry.Y+monitor.Geometry.Height-1))
+		if distance := distanceX*distanceX + distanceY*distanceY; nearest < 0 || distance < nearestDistance {
+			nearest, nearestDistance = index, distance
+		}
+	}
+	return nearest
+}
+
+/*
+GetMonitorOf Gets the monitor a rectangle (e.g. the frame of a window) is on: the one that shares the biggest area
+with it, or the nearest one to its center if it's not on any monitor.
+
+Parameters:
+  - monitors: Monitors returned by GetMonitors.
+  - rectangle: Rectangle relative to the root window.
+
+Returns:
+  - The index of the monitor, -1 if monitors is empty
+*/
+func GetMonitorOf(monitors []Monitor, rectangle Rectangle) int {
+	biggest, biggestArea := -1, 0
+	for index, monitor := range monitors {
+		intersection := monitor.Geometry.Intersection(rectangle)
+		if area := intersection.Width * intersection.Height; area > biggestArea {
+			biggest, biggestArea = index, area
+		}
+	}
+	if biggest >= 0 {
+		return biggest
+	}
+	return GetMonitorAt(monitors, rectangle.X+rectangle.Width/2, rectangle.Y+rectangle.Height/2)
+}
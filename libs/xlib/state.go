@@ -0,0 +1,125 @@
+package xlib
+
+//#include <X11/Xlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// WindowState State of a window that can be changed with the property "_NET_WM_STATE".
+type WindowState string
+
+const (
+	StateMaximized  WindowState = "maximized"  // "_NET_WM_STATE_MAXIMIZED_VERT" and "_NET_WM_STATE_MAXIMIZED_HORZ"
+	StateFullscreen WindowState = "fullscreen" // "_NET_WM_STATE_FULLSCREEN"
+	StateAbove      WindowState = "above"      // "_NET_WM_STATE_ABOVE", always on top
+	StateSticky     WindowState = "sticky"     // "_NET_WM_STATE_STICKY", visible on every desktop
+	StateShaded     WindowState = "shaded"     // "_NET_WM_STATE_SHADED", rolled up to the title bar
+	StateHidden     WindowState = "hidden"     // "_NET_WM_STATE_HIDDEN", minimized. It can only be read, see IconifyWindow
+
+	// States that tell taskbars and pagers to ignore the window, they are not in WindowStates
+	StateSkipTaskbar WindowState = "skip_taskbar" // "_NET_WM_STATE_SKIP_TASKBAR", not shown in taskbars
+	StateSkipPager   WindowState = "skip_pager"   // "_NET_WM_STATE_SKIP_PAGER", not shown in pagers
+)
+
+// StateAction Action requested on a window state, the values are the ones defined by EWMH for "_NET_WM_STATE".
+type StateAction int64
+
+const (
+	StateRemove StateAction = iota // _NET_WM_STATE_REMOVE
+	StateAdd                       // _NET_WM_STATE_ADD
+	StateToggle                    // _NET_WM_STATE_TOGGLE
+)
+
+// WindowStates States that can be changed with ChangeWindowState
+var WindowStates = []WindowState{StateMaximized, StateFullscreen, StateAbove, StateSticky, StateShaded}
+
+// Atoms of every window state
+var windowStateAtoms = map[WindowState][]string{
+	StateMaximized:   {"_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ"},
+	StateFullscreen:  {"_NET_WM_STATE_FULLSCREEN"},
+	StateAbove:       {"_NET_WM_STATE_ABOVE"},
+	StateSticky:      {"_NET_WM_STATE_STICKY"},
+	StateShaded:      {"_NET_WM_STATE_SHADED"},
+	StateHidden:      {"_NET_WM_STATE_HIDDEN"},
+	StateSkipTaskbar: {"_NET_WM_STATE_SKIP_TASKBAR"},
+	StateSkipPager:   {"_NET_WM_STATE_SKIP_PAGER"},
+}
+
+// Value of WM_STATE for a window that is iconified (ICCCM)
+const iconicState = 3
+
+/*
+ChangeWindowState Asks the window manager to add, remove or toggle a state of a window sending the client message
+"_NET_WM_STATE".
+
+Parameters:
+  - window: Specifies the window whose state you want to change.
+  - action: Specifies whether the state is added, removed or toggled.
+  - state: Specifies the state, any of WindowStates.
+
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) ChangeWindowState(window Window, action StateAction, state WindowState) (bool, error) {
+	atomNames, exists := windowStateAtoms[state]
+	if !exists || state == StateHidden {
+		return false, fmt.Errorf("the window state \"%s\" can't be changed", state)
+	}
+	conn.lock()
+	defer conn.unlock()
+	data := []int64{int64(action), 0, 0, 2} // Source indication 2 (pager)
+	for index, atomName := range atomNames {
+		data[index+1] = int64(conn.internAtom(atomName))
+	}
+	return conn.SendClientMessage(window, "_NET_WM_STATE", data...)
+}
+
+/*
+GetWindowStates Gets the states of a window based on the property "_NET_WM_STATE".
+
+Returns:
+  - A map with the states the window has
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) GetWindowStates(window Window) (map[WindowState]bool, error) {
+	conn.lock()
+	defer conn.unlock()
+	states := map[WindowState]bool{}
+	netWMState, err := conn.GetWindowProperty(window, "_NET_WM_STATE")
+	if netWMState == nil || err != nil {
+		if IsWindowGone(err) {
+			return nil, err
+		}
+		return states, nil // The window doesn't have any state
+	}
+	atoms := map[C.Atom]bool{}
+	for _, atom := range netWMState.GetLong() {
+		atoms[C.Atom(atom)] = true
+	}
+	for state, atomNames := range windowStateAtoms {
+		hasState := true
+		for _, atomName := range atomNames {
+			hasState = hasState && atoms[conn.internAtom(atomName)]
+		}
+		if hasState {
+			states[state] = true
+		}
+	}
+	return states, nil
+}
+
+// IconifyWindow Asks the window manager to iconify (minimize) a window sending the client message "WM_CHANGE_STATE".
+func (conn *Conn) IconifyWindow(window Window) (bool, error) {
+	return conn.SendClientMessage(window, "WM_CHANGE_STATE", iconicState)
+}
+
+// Gets the atom of a name, creating it if it doesn't exist. The display must be locked.
+func (conn *Conn) internAtom(name string) C.Atom {
+	atomName := C.CString(name)
+	defer C.XFree(unsafe.Pointer(atomName))
+	return C.XInternAtom(conn.display, atomName, C.False)
+}
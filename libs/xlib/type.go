@@ -0,0 +1,113 @@
+package xlib
+
+//#include <X11/Xlib.h>
+import "C"
+
+// WindowType Functional type of a window based on the property "_NET_WM_WINDOW_TYPE".
+type WindowType string
+
+const (
+	TypeNormal       WindowType = "normal"        // "_NET_WM_WINDOW_TYPE_NORMAL", top-level window
+	TypeDialog       WindowType = "dialog"        // "_NET_WM_WINDOW_TYPE_DIALOG"
+	TypeDesktop      WindowType = "desktop"       // "_NET_WM_WINDOW_TYPE_DESKTOP", window that draws the desktop
+	TypeDock         WindowType = "dock"          // "_NET_WM_WINDOW_TYPE_DOCK", panels and docks
+	TypeToolbar      WindowType = "toolbar"       // "_NET_WM_WINDOW_TYPE_TOOLBAR", torn off toolbar
+	TypeMenu         WindowType = "menu"          // "_NET_WM_WINDOW_TYPE_MENU", torn off menu
+	TypeUtility      WindowType = "utility"       // "_NET_WM_WINDOW_TYPE_UTILITY", palettes and toolboxes
+	TypeSplash       WindowType = "splash"        // "_NET_WM_WINDOW_TYPE_SPLASH", splash screen while an app starts
+	TypeDropdownMenu WindowType = "dropdown_menu" // "_NET_WM_WINDOW_TYPE_DROPDOWN_MENU"
+	TypePopupMenu    WindowType = "popup_menu"    // "_NET_WM_WINDOW_TYPE_POPUP_MENU"
+	TypeTooltip      WindowType = "tooltip"       // "_NET_WM_WINDOW_TYPE_TOOLTIP"
+	TypeNotification WindowType = "notification"  // "_NET_WM_WINDOW_TYPE_NOTIFICATION"
+	TypeCombo        WindowType = "combo"         // "_NET_WM_WINDOW_TYPE_COMBO"
+	TypeDND          WindowType = "dnd"           // "_NET_WM_WINDOW_TYPE_DND", dragged object
+)
+
+// WindowTypes All the window types defined by EWMH
+var WindowTypes = []WindowType{
+	TypeNormal,
+	TypeDialog,
+	TypeDesktop,
+	TypeDock,
+	TypeToolbar,
+	TypeMenu,
+	TypeUtility,
+	TypeSplash,
+	TypeDropdownMenu,
+	TypePopupMenu,
+	TypeTooltip,
+	TypeNotification,
+	TypeCombo,
+	TypeDND,
+}
+
+// Atoms of every window type
+var windowTypeAtoms = map[WindowType]string{
+	TypeNormal:       "_NET_WM_WINDOW_TYPE_NORMAL",
+	TypeDialog:       "_NET_WM_WINDOW_TYPE_DIALOG",
+	TypeDesktop:      "_NET_WM_WINDOW_TYPE_DESKTOP",
+	TypeDock:         "_NET_WM_WINDOW_TYPE_DOCK",
+	TypeToolbar:      "_NET_WM_WINDOW_TYPE_TOOLBAR",
+	TypeMenu:         "_NET_WM_WINDOW_TYPE_MENU",
+	TypeUtility:      "_NET_WM_WINDOW_TYPE_UTILITY",
+	TypeSplash:       "_NET_WM_WINDOW_TYPE_SPLASH",
+	TypeDropdownMenu: "_NET_WM_WINDOW_TYPE_DROPDOWN_MENU",
+	TypePopupMenu:    "_NET_WM_WINDOW_TYPE_POPUP_MENU",
+	TypeTooltip:      "_NET_WM_WINDOW_TYPE_TOOLTIP",
+	TypeNotification: "_NET_WM_WINDOW_TYPE_NOTIFICATION",
+	TypeCombo:        "_NET_WM_WINDOW_TYPE_COMBO",
+	TypeDND:          "_NET_WM_WINDOW_TYPE_DND",
+}
+
+/*
+GetWindowType Gets the type of a window based on the property "_NET_WM_WINDOW_TYPE". The property is a list in order of
+preference and the first known type is used. A window without a known type is a dialog if it has "WM_TRANSIENT_FOR",
+otherwise it's a normal one (EWMH).
+
+Returns:
+  - The type of the window
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) GetWindowType(window Window) (WindowType, error) {
+	conn.lock()
+	defer conn.unlock()
+	netWMWindowType, err := conn.GetWindowProperty(window, "_NET_WM_WINDOW_TYPE")
+	if IsWindowGone(err) {
+		return TypeNormal, err
+	}
+	if netWMWindowType != nil && err == nil {
+		types := map[C.Atom]WindowType{}
+		for windowType, atomName := range windowTypeAtoms {
+			types[conn.internAtom(atomName)] = windowType
+		}
+		for _, atom := range netWMWindowType.GetLong() {
+			if windowType, exists := types[C.Atom(atom)]; exists {
+				return windowType, nil
+			}
+		}
+	}
+	transientFor, err := conn.GetTransientFor(window)
+	if IsWindowGone(err) {
+		return TypeNormal, err
+	}
+	if transientFor != Window(0) {
+		return TypeDialog, nil
+	}
+	return TypeNormal, nil
+}
+
+/*
+GetTransientFor Gets the window a window is transient for based on the property "WM_TRANSIENT_FOR", e.g. the main
+window of the application that opened a dialog.
+
+Returns:
+  - The window it's transient for, 0 if the window is not transient for any window
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) GetTransientFor(window Window) (Window, error) {
+	transientFor, err := conn.GetWindowProperty(window, "WM_TRANSIENT_FOR")
+	if err != nil || transientFor == nil || len(transientFor.GetLong()) == 0 {
+		return Window(0), err
+	}
+	return Window(transientFor.GetLong()[0]), nil
+}
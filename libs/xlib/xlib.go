@@ -20,8 +20,10 @@ import "C"
 import (
 	"fmt"
 	"reflect"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -34,7 +36,16 @@ type (
 	Button  = int
 )
 
-var display *Display
+// Conn Connection to the X server. Every request is sent with the display locked (XLockDisplay), so a Conn can be
+// shared by several goroutines; open another Conn to get an independent connection (e.g. for watching events).
+type Conn struct {
+	display    *Display
+	root       Window
+	timeWindow Window // Window owned by the connection used to get timestamps from the X server
+}
+
+// XInitThreads must be called once, before any other connection is opened
+var initThreadsOnce sync.Once
 
 const (
 	CURRENTWINDOW Window = iota
@@ -144,21 +155,41 @@ type MouseLocation struct {
 	Window       Window // Window where the mouse is
 }
 
-// OpenDisplay Open connection to the X server
-func OpenDisplay() {
-	display = C.XOpenDisplay(nil)
+// OpenConn Open a new connection to the X server, wrapper for XOpenDisplay
+func OpenConn() (*Conn, error) {
+	initThreadsOnce.Do(func() { C.XInitThreads() })
+	display := C.XOpenDisplay(nil)
+	if display == nil {
+		return nil, fmt.Errorf("an error occurred opening a connection to the X server")
+	}
+	trackDisplay(display)
+	return &Conn{display: display, root: C.XDefaultRootWindow(display)}, nil
 }
 
-// CloseDisplay Close connection to the X server, wrapper for XCloseDisplay
-func CloseDisplay() {
-	if display != nil {
-		C.XCloseDisplay(display)
+// Close Close the connection to the X server, wrapper for XCloseDisplay. The Conn can't be used afterwards.
+func (conn *Conn) Close() {
+	if conn.display != nil {
+		untrackDisplay(conn.display)
+		C.XCloseDisplay(conn.display)
+		conn.display = nil
 	}
 }
 
+// Locks the display for the calling goroutine, calls can be nested as long as the goroutine stays on the same thread
+func (conn *Conn) lock() {
+	runtime.LockOSThread()
+	C.XLockDisplay(conn.display)
+}
+
+// Unlocks the display locked with lock
+func (conn *Conn) unlock() {
+	C.XUnlockDisplay(conn.display)
+	runtime.UnlockOSThread()
+}
+
 // GetRootWindow Get root window, wrapper for XDefaultRootWindow
-func GetRootWindow() Window {
-	return C.XDefaultRootWindow(display)
+func (conn *Conn) GetRootWindow() Window {
+	return conn.root
 }
 
 /*
@@ -170,9 +201,12 @@ Parameters:
 
 Returns:
   - A PropertyResult object with the result
-  - Possible error or nil
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
 */
-func GetWindowProperty(window Window, property string) (*PropertyResult, error) {
+func (conn *Conn) GetWindowProperty(window Window, property string) (*PropertyResult, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	defer func() {
 		err := recover()
 		if err == nil {
@@ -206,6 +240,7 @@ func GetWindowProperty(window Window, property string) (*PropertyResult, error)
 	var actualTypeOfTheProperty string
 	finalNItems := uint64(0)
 
+	trap := trapErrors(display)
 	for {
 		// Call C Function XGetWindowProperty
 		result := C.XGetWindowProperty(
@@ -223,6 +258,9 @@ func GetWindowProperty(window Window, property string) (*PropertyResult, error)
 			&propReturn,
 		)
 		if result != C.Success || (actualFormatReturn == 0 && bytesAfterReturn == 0) {
+			if err := trap.untrap("XGetWindowProperty", false); err != nil {
+				return nil, err
+			}
 			return nil, fmt.Errorf("property \"%s\" not found on window %d", property, window)
 		}
 		if len(actualTypeOfTheProperty) == 0 {
@@ -245,6 +283,7 @@ func GetWindowProperty(window Window, property string) (*PropertyResult, error)
 			break
 		}
 	}
+	_ = trap.untrap("XGetWindowProperty", false)
 	propertyResult := &PropertyResult{
 		Format:         int(actualFormatReturn),
 		TypeOfProperty: actualTypeOfTheProperty,
@@ -261,6 +300,8 @@ ChangeWindowProperty This function is a wrapper around XChangeProperty.
 
 Parameters:
 
+  - conn: Specifies the connection to the X server.
+
   - window: Specifies the window whose property you want to change.
 
   - property: Specifies the property name you want to change.
@@ -275,9 +316,10 @@ Parameters:
 
 Returns:
   - A boolean indicating if the property was changed succesfully or not
-  - Possible error or nil
+  - Possible error or nil, a *XError if the X server reported an error
 */
 func ChangeWindowProperty[T string | []int8 | []int16 | []int64](
+	conn *Conn,
 	window Window,
 	property string,
 	typeOfProperty string,
@@ -309,6 +351,10 @@ func ChangeWindowProperty[T string | []int8 | []int16 | []int64](
 		return false, fmt.Errorf("the format %d is not valid, possible values are 8, 16 or 32", format)
 	}
 
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
+
 	propertyName := C.CString(property)
 	atomProperty := C.XInternAtom(display, propertyName, C.False)
 	defer C.XFree(unsafe.Pointer(propertyName))
@@ -317,6 +363,7 @@ func ChangeWindowProperty[T string | []int8 | []int16 | []int64](
 	atomPropertyType := C.XInternAtom(display, propertyTypeName, C.False)
 	defer C.XFree(unsafe.Pointer(propertyTypeName))
 
+	trap := trapErrors(display)
 	result := C.XChangeProperty(
 		display,
 		window,
@@ -327,7 +374,9 @@ func ChangeWindowProperty[T string | []int8 | []int16 | []int64](
 		(*C.uchar)(reflect.ValueOf(newValue).UnsafePointer()),
 		C.int(len(newValue)),
 	)
-	C.XFlush(display)
+	if err := trap.untrap("XChangeProperty", true); err != nil {
+		return false, err
+	}
 	if result == C.True {
 		return true, nil
 	} else {
@@ -336,7 +385,10 @@ func ChangeWindowProperty[T string | []int8 | []int16 | []int64](
 }
 
 // QueryPointer Wrapper around XQueryPointer
-func QueryPointer(window Window) (*QueryPointerResult, error) {
+func (conn *Conn) QueryPointer(window Window) (*QueryPointerResult, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	var rootReturn Window
 	var childReturn Window
 	var rootXReturn C.int
@@ -344,6 +396,7 @@ func QueryPointer(window Window) (*QueryPointerResult, error) {
 	var winXReturn C.int
 	var winYReturn C.int
 	var maskReturn C.uint
+	trap := trapErrors(display)
 	result := C.XQueryPointer(
 		display,
 		window,
@@ -355,6 +408,9 @@ func QueryPointer(window Window) (*QueryPointerResult, error) {
 		&winYReturn,
 		&maskReturn,
 	)
+	if err := trap.untrap("XQueryPointer", false); err != nil {
+		return nil, err
+	}
 	if result == C.False {
 		return nil, fmt.Errorf(
 			"an error occurred querying the pointer position for window %d with XQueryPointer",
@@ -373,12 +429,19 @@ func QueryPointer(window Window) (*QueryPointerResult, error) {
 }
 
 // QueryTree Wrapper around XQueryTree
-func QueryTree(window Window) (*QueryTreeResult, error) {
+func (conn *Conn) QueryTree(window Window) (*QueryTreeResult, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	var rootReturn Window
 	var parentReturn Window
 	var childrenReturn *Window
 	var nchildrenReturn C.uint
+	trap := trapErrors(display)
 	result := C.XQueryTree(display, window, &rootReturn, &parentReturn, &childrenReturn, &nchildrenReturn)
+	if err := trap.untrap("XQueryTree", false); err != nil {
+		return nil, err
+	}
 	if result == C.False {
 		return nil, fmt.Errorf("an error occurred querying the window tree for window %d with XQueryTree", window)
 	}
@@ -392,9 +455,16 @@ func QueryTree(window Window) (*QueryTreeResult, error) {
 }
 
 // GetWindowAttributes Wrapper around XGetWindowAttributes
-func GetWindowAttributes(window Window) (*WindowAttributesResult, error) {
+func (conn *Conn) GetWindowAttributes(window Window) (*WindowAttributesResult, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	var windowAttributes C.XWindowAttributes
+	trap := trapErrors(display)
 	result := C.XGetWindowAttributes(display, window, &windowAttributes)
+	if err := trap.untrap("XGetWindowAttributes", false); err != nil {
+		return nil, err
+	}
 	if result == C.False {
 		return nil, fmt.Errorf(
 			"an error occurred getting window attributes for window %d with XGetWindowAttributes",
@@ -418,7 +488,7 @@ func GetWindowAttributes(window Window) (*WindowAttributesResult, error) {
 }
 
 // GetWindowIcon Function that returns a *gdk.Pixbuf containing the window icon based on the property "_NET_WM_ICON"
-func GetWindowIcon(window Window) *gdk.Pixbuf {
+func (conn *Conn) GetWindowIcon(window Window) *gdk.Pixbuf {
 	// Internal struct representing a window icon
 	type windowIcon struct {
 		width  int
@@ -429,7 +499,7 @@ func GetWindowIcon(window Window) *gdk.Pixbuf {
 	var icons []windowIcon // Slice with all available icons for a window
 
 	// Get all buffer from property "_NET_WM_ICON"
-	icons_, err := GetWindowProperty(window, "_NET_WM_ICON")
+	icons_, err := conn.GetWindowProperty(window, "_NET_WM_ICON")
 	if icons_ == nil && err != nil {
 		return nil
 	} else if len(icons_.longResult) == 0 {
@@ -505,16 +575,19 @@ ClickWindow function simulates a click (mouse left button press / release) on a
 
 If window 0 is passed, then the click will be performed using XTest library (XTestFakeButtonEvent), else, it will use XSendEvent.
 */
-func ClickWindow(window Window, button Button) bool {
-	if !MousePress(window, button) {
+func (conn *Conn) ClickWindow(window Window, button Button) bool {
+	if !conn.MousePress(window, button) {
 		fmt.Printf("MousePress on window %d failed, aborting click.\n", window)
 		return false
 	}
 	time.Sleep(time.Microsecond * 12)
-	return MouseRelease(window, button)
+	return conn.MouseRelease(window, button)
 }
 
-func SimulateMouseButton(window Window, button Button, pressed bool) bool {
+func (conn *Conn) SimulateMouseButton(window Window, button Button, pressed bool) bool {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	if window == CURRENTWINDOW { // Send event to current window using XTest library
 		retCode := C.XTestFakeButtonEvent(
 			display,
@@ -528,7 +601,7 @@ func SimulateMouseButton(window Window, button Button, pressed bool) bool {
 		}
 	} else {
 		// Send to specific window
-		mouseLocation, err := GetMouseLocation()
+		mouseLocation, err := conn.GetMouseLocation()
 		if mouseLocation == nil && err != nil {
 			return false
 		}
@@ -542,7 +615,7 @@ func SimulateMouseButton(window Window, button Button, pressed bool) bool {
 		xButtonEvent.same_screen = C.True
 		xButtonEvent.subwindow = C.None
 		xButtonEvent.time = C.CurrentTime
-		if queryPointerResult, err := QueryPointer(C.default_root_window(display)); queryPointerResult != nil && err == nil {
+		if queryPointerResult, err := conn.QueryPointer(conn.root); queryPointerResult != nil && err == nil {
 			xButtonEvent.state = C.uint(queryPointerResult.MaskReturn)
 		}
 		if pressed {
@@ -572,12 +645,12 @@ func SimulateMouseButton(window Window, button Button, pressed bool) bool {
 	return false
 }
 
-func MousePress(window Window, button Button) bool {
-	return SimulateMouseButton(window, button, true)
+func (conn *Conn) MousePress(window Window, button Button) bool {
+	return conn.SimulateMouseButton(window, button, true)
 }
 
-func MouseRelease(window Window, button Button) bool {
-	return SimulateMouseButton(window, button, false)
+func (conn *Conn) MouseRelease(window Window, button Button) bool {
+	return conn.SimulateMouseButton(window, button, false)
 }
 
 /*
@@ -590,13 +663,16 @@ Returns:
   - integer with the screen number
   - Window where the mouse is
 */
-func GetMouseLocation() (*MouseLocation, error) {
+func (conn *Conn) GetMouseLocation() (*MouseLocation, error) {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
 	var queryPointerResult *QueryPointerResult
 	var errQueryPointer error
 	var screenNumber int
 	for i := range int(C.screen_count(display)) {
 		screen := C.screen_of_display(display, C.int(i))
-		if queryPointerResult, errQueryPointer = QueryPointer(screen.root); queryPointerResult != nil &&
+		if queryPointerResult, errQueryPointer = conn.QueryPointer(screen.root); queryPointerResult != nil &&
 			errQueryPointer == nil {
 			screenNumber = i
 			break
@@ -609,9 +685,9 @@ func GetMouseLocation() (*MouseLocation, error) {
 	windowWhereMouseIs := queryPointerResult.Child
 	if windowWhereMouseIs != queryPointerResult.Root && windowWhereMouseIs != CURRENTWINDOW {
 		winn := Window(0)
-		result := FindWindowClient(windowWhereMouseIs, true, &winn)
+		result := conn.FindWindowClient(windowWhereMouseIs, true, &winn)
 		if !result {
-			result = FindWindowClient(windowWhereMouseIs, false, &winn)
+			result = conn.FindWindowClient(windowWhereMouseIs, false, &winn)
 		}
 		if result {
 			windowWhereMouseIs = winn
@@ -633,7 +709,7 @@ FindWindowClient Find a client window (child) in a given window.
 If lookForParent is true, it will look for a client window that is a parent of the window given.
 If lookForParent is false, it will look for a client window that is a child of the window given.
 */
-func FindWindowClient(window Window, lookForParent bool, windowReturn *Window) bool {
+func (conn *Conn) FindWindowClient(window Window, lookForParent bool, windowReturn *Window) bool {
 	done := false
 	for !done {
 		if window == CURRENTWINDOW {
@@ -641,14 +717,14 @@ func FindWindowClient(window Window, lookForParent bool, windowReturn *Window) b
 		}
 		keepLooking := false
 		lookForAlternativeProperty := false
-		if netWMStateProperty, err := GetWindowProperty(window, "_NET_WM_STATE"); netWMStateProperty == nil &&
+		if netWMStateProperty, err := conn.GetWindowProperty(window, "_NET_WM_STATE"); netWMStateProperty == nil &&
 			err != nil {
 			lookForAlternativeProperty = true
 		} else if netWMStateProperty.NumberOfItems == 0 {
 			keepLooking = true
 		}
 		if lookForAlternativeProperty {
-			if wmStateProperty, err := GetWindowProperty(window, "WM_STATE"); wmStateProperty == nil &&
+			if wmStateProperty, err := conn.GetWindowProperty(window, "WM_STATE"); wmStateProperty == nil &&
 				err != nil {
 				keepLooking = true
 			} else if wmStateProperty.NumberOfItems == 0 {
@@ -657,7 +733,7 @@ func FindWindowClient(window Window, lookForParent bool, windowReturn *Window) b
 		}
 		if keepLooking {
 			// This window doesn't have _NET_WM_STATE or WM_STATE property, keep searching
-			queryTreeResult, errQueryTree := QueryTree(window)
+			queryTreeResult, errQueryTree := conn.QueryTree(window)
 			if queryTreeResult == nil && errQueryTree != nil {
 				return false
 			}
@@ -666,7 +742,7 @@ func FindWindowClient(window Window, lookForParent bool, windowReturn *Window) b
 			} else {
 				done = true
 				for _, child := range queryTreeResult.Children {
-					if FindWindowClient(child, lookForParent, &window) {
+					if conn.FindWindowClient(child, lookForParent, &window) {
 						*windowReturn = window
 						break
 					}
@@ -684,8 +760,8 @@ func FindWindowClient(window Window, lookForParent bool, windowReturn *Window) b
 }
 
 // GetActiveWindow gets the current actual window by querying the property "_NET_ACTIVE_WINDOW" on the root window.
-func GetActiveWindow() (bool, Window) {
-	activeWindow, err := GetWindowProperty(GetRootWindow(), "_NET_ACTIVE_WINDOW")
+func (conn *Conn) GetActiveWindow() (bool, Window) {
+	activeWindow, err := conn.GetWindowProperty(conn.GetRootWindow(), "_NET_ACTIVE_WINDOW")
 	if activeWindow == nil && err != nil {
 		return false, Window(0)
 	} else if activeWindow.NumberOfItems == 0 {
@@ -694,50 +770,70 @@ func GetActiveWindow() (bool, Window) {
 	return true, Window(activeWindow.longResult[0])
 }
 
-func ActivateWindow(window Window) bool {
-	// Try to change current desktop to the window's desktop if we're not in the same desktop
-	if windowDesktopProp, _ := GetWindowProperty(window, "_NET_WM_DESKTOP"); windowDesktopProp != nil &&
-		windowDesktopProp.NumberOfItems > 0 {
-		if _, currentDesktop := GetCurrentDesktop(); currentDesktop >= 0 {
-			windowDesktop := int(windowDesktopProp.longResult[0])
-			if windowDesktop >= 0 && currentDesktop != windowDesktop {
-				fmt.Printf("Changing current desktop from %d to %d\n", currentDesktop, windowDesktop)
-				ChangeCurrentDesktop(int(windowDesktopProp.longResult[0]))
-			}
-		}
-	}
+/*
+ActivateWindow Activates a window sending the client message "_NET_ACTIVE_WINDOW" (strategy ActivationEWMH), changing
+the current desktop first if the window is on another desktop.
 
-	windowAttributes, err := GetWindowAttributes(window)
-	if windowAttributes == nil && err != nil {
-		return false
-	} else if windowAttributes.Screen == nil {
-		return false
-	}
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) ActivateWindow(window Window) (bool, error) {
+	return conn.ActivateWindowWith(window, ActivationEWMH)
+}
+
+/*
+SendClientMessage Sends a client message (format 32) to the root window, the way EWMH requests are made to the window
+manager, e.g. "_NET_ACTIVE_WINDOW", "_NET_CLOSE_WINDOW" or "_NET_WM_STATE".
 
+Parameters:
+  - window: Specifies the window the message is about.
+  - messageType: Specifies the name of the message type.
+  - data: Specifies up to 5 longs sent as the data of the message, the missing ones are sent as 0.
+
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) SendClientMessage(window Window, messageType string, data ...int64) (bool, error) {
 	var xClientMessageEvent C.XClientMessageEvent
+	longs := (*[5]C.long)(unsafe.Pointer(&xClientMessageEvent.data))
+	if len(data) > len(longs) {
+		return false, fmt.Errorf("a client message can't hold more than %d longs, got %d", len(longs), len(data))
+	}
+	for index, value := range data {
+		longs[index] = C.long(value)
+	}
+
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
+
+	messageTypeName := C.CString(messageType)
+	defer C.XFree(unsafe.Pointer(messageTypeName))
 	xClientMessageEvent._type = C.int(C.ClientMessage)
 	xClientMessageEvent.display = display
 	xClientMessageEvent.window = window
-	netActiveWindow := C.CString("_NET_ACTIVE_WINDOW")
-	defer C.XFree(unsafe.Pointer(netActiveWindow))
-	xClientMessageEvent.message_type = C.XInternAtom(display, netActiveWindow, C.False)
+	xClientMessageEvent.message_type = C.XInternAtom(display, messageTypeName, C.False)
 	xClientMessageEvent.format = C.int(32)
-	xClientMessageEvent.data = [40]byte{byte(2), byte(C.CurrentTime)}
 
+	trap := trapErrors(display)
 	result := C.XSendEvent(
 		display,
-		windowAttributes.Screen.root,
+		conn.root,
 		C.False,
 		C.SubstructureNotifyMask|C.SubstructureRedirectMask,
 		(*C.XEvent)(unsafe.Pointer(&xClientMessageEvent)),
 	)
-	C.XFlush(display)
-	return result == C.True
+	if err := trap.untrap("XSendEvent", true); err != nil {
+		return false, err
+	}
+	return result != C.False, nil
 }
 
 // GetCurrentDesktop get current desktop based on property "_NET_CURRENT_DESKTOP" on the root window
-func GetCurrentDesktop() (bool, int) {
-	currentDesktopProp, err := GetWindowProperty(GetRootWindow(), "_NET_CURRENT_DESKTOP")
+func (conn *Conn) GetCurrentDesktop() (bool, int) {
+	currentDesktopProp, err := conn.GetWindowProperty(conn.GetRootWindow(), "_NET_CURRENT_DESKTOP")
 	if currentDesktopProp == nil && err != nil {
 		return false, -1
 	} else if currentDesktopProp.NumberOfItems == 0 {
@@ -750,12 +846,14 @@ func GetCurrentDesktop() (bool, int) {
 	return false, -1
 }
 
-func ChangeCurrentDesktop(desktop int) bool {
+func (conn *Conn) ChangeCurrentDesktop(desktop int) bool {
+	conn.lock()
+	defer conn.unlock()
 	if desktop < 0 {
 		return false
 	}
 
-	amountOfAvailableDesktopsProp, err := GetWindowProperty(GetRootWindow(), "_NET_NUMBER_OF_DESKTOPS")
+	amountOfAvailableDesktopsProp, err := conn.GetWindowProperty(conn.GetRootWindow(), "_NET_NUMBER_OF_DESKTOPS")
 	if amountOfAvailableDesktopsProp == nil && err != nil {
 		return false
 	} else if amountOfAvailableDesktopsProp.NumberOfItems == 0 {
@@ -766,52 +864,30 @@ func ChangeCurrentDesktop(desktop int) bool {
 		return false
 	}
 
-	res, currentDesktop := GetCurrentDesktop()
+	res, currentDesktop := conn.GetCurrentDesktop()
 	if !res || currentDesktop < 0 || desktop == currentDesktop {
 		return false
 	}
 
-	var xClientMessageEvent C.XClientMessageEvent
-	xClientMessageEvent._type = C.int(C.ClientMessage)
-	xClientMessageEvent.display = display
-	xClientMessageEvent.window = GetRootWindow()
-	netCurrentDesktop := C.CString("_NET_CURRENT_DESKTOP")
-	defer C.XFree(unsafe.Pointer(netCurrentDesktop))
-	xClientMessageEvent.message_type = C.XInternAtom(display, netCurrentDesktop, C.False)
-	xClientMessageEvent.format = C.int(32)
-	xClientMessageEvent.data = [40]byte{byte(desktop), byte(C.CurrentTime)}
-
-	result := C.XSendEvent(
-		display,
-		GetRootWindow(),
-		C.False,
-		C.SubstructureNotifyMask|C.SubstructureRedirectMask,
-		(*C.XEvent)(unsafe.Pointer(&xClientMessageEvent)),
-	)
-	C.XFlush(display)
-	return result == C.True
+	result, _ := conn.SendClientMessage(conn.GetRootWindow(), "_NET_CURRENT_DESKTOP", int64(desktop), C.CurrentTime)
+	return result
 }
 
-func WaitForWindowActivate(window Window, active bool) bool {
-	var result bool
-	activeWindow := Window(0)
-	maxTries := 500
+/*
+MoveWindowToDesktop Moves a window to another desktop sending the client message "_NET_WM_DESKTOP".
 
-	for range maxTries {
-		if active {
-			if activeWindow == window {
-				break
-			}
-		} else {
-			if activeWindow != Window(0) && activeWindow != window {
-				break
-			}
-		}
-		result, activeWindow = GetActiveWindow()
-		if !result {
-			return false
-		}
-		time.Sleep(time.Microsecond * 30000)
+Parameters:
+  - window: Specifies the window to move.
+  - desktop: Specifies the desktop, 0xFFFFFFFF means all desktops.
+
+Returns:
+  - A boolean indicating if the message was sent
+  - Possible error or nil, a *XError if the X server reported an error
+*/
+func (conn *Conn) MoveWindowToDesktop(window Window, desktop int) (bool, error) {
+	if desktop < 0 {
+		return false, fmt.Errorf("the desktop %d is not valid", desktop)
 	}
-	return true
+	// Source indication 2 (pager)
+	return conn.SendClientMessage(window, "_NET_WM_DESKTOP", int64(desktop), 2)
 }
@@ -0,0 +1,74 @@
+package xlib
+
+//#cgo pkg-config: xrandr
+//#include <X11/Xlib.h>
+//#include <X11/extensions/Xrandr.h>
+import "C"
+
+import "unsafe"
+
+/*
+GetMonitors Gets the active monitors of the screen with XRRGetMonitors.
+
+Returns:
+  - The monitors, if XRandR is not available (or reports no monitors) the whole screen is returned as the only monitor
+*/
+func (conn *Conn) GetMonitors() []Monitor {
+	conn.lock()
+	defer conn.unlock()
+	display := conn.display
+
+	var eventBase, errorBase C.int
+	if C.XRRQueryExtension(display, &eventBase, &errorBase) != C.False {
+		var count C.int
+		trap := trapErrors(display)
+		monitorInfos := C.XRRGetMonitors(display, conn.root, C.True, &count)
+		_ = trap.untrap("XRRGetMonitors", true)
+		if monitorInfos != nil {
+			defer C.XRRFreeMonitors(monitorInfos)
+			var monitors []Monitor
+			for _, monitorInfo := range unsafe.Slice(monitorInfos, int(count)) {
+				var name string
+				if atomName := C.XGetAtomName(display, monitorInfo.name); atomName != nil {
+					name = C.GoString(atomName)
+					C.XFree(unsafe.Pointer(atomName))
+				}
+				monitors = append(monitors, Monitor{
+					Name: name,
+					Geometry: Rectangle{
+						X:      int(monitorInfo.x),
+						Y:      int(monitorInfo.y),
+						Width:  int(monitorInfo.width),
+						Height: int(monitorInfo.height),
+					},
+					Primary: monitorInfo.primary != C.False,
+				})
+			}
+			if len(monitors) > 0 {
+				return monitors
+			}
+		}
+	}
+
+	screen := C.XDefaultScreen(display)
+	return []Monitor{{
+		Geometry: Rectangle{Width: int(C.XDisplayWidth(display, screen)), Height: int(C.XDisplayHeight(display, screen))},
+		Primary:  true,
+	}}
+}
+
+/*
+GetWindowMonitor Gets the monitor a window is on, based on the geometry of its frame.
+
+Returns:
+  - The monitor of the window
+  - Possible error or nil, a *XError if the X server reported an error (e.g. BadWindow when the window was closed)
+*/
+func (conn *Conn) GetWindowMonitor(window Window) (*Monitor, error) {
+	geometry, err := conn.GetWindowGeometry(window)
+	if geometry == nil || err != nil {
+		return nil, err
+	}
+	monitors := conn.GetMonitors()
+	return &monitors[GetMonitorOf(monitors, geometry.Frame)], nil
+}
@@ -44,13 +44,7 @@ func newApplication(application *gtk.Application) *mainApplication {
 // This function is for configuration where all the custom signals are created and their callbacks.
 // Callback of "startup" signal of the application.
 func (app *mainApplication) startup() {
-	app.config = configparser.New()
-	if result, _ := configFile.IsFile(); result {
-		config, err := configparser.NewConfigParserFromFile(configFile.String())
-		if err == nil {
-			app.config = config
-		}
-	}
+	app.loadConfig()
 
 	// --------------------------------- APPLICATION CUSTOM SIGNALS -----------------------------
 
@@ -74,7 +68,8 @@ func (app *mainApplication) startup() {
 	// Handler
 	app.application.Connect("app-exit", func(application *gtk.Application) {
 		keyboard.ExitListener()
-		xlib.CloseDisplay() // Close connection to X server
+		app.gui.StopWindowTracker() // Stop watching the events of the X server
+		xConn.Close()               // Close connection to X server
 		application.Quit()
 	})
 
@@ -90,12 +85,7 @@ func (app *mainApplication) startup() {
 	app.application.Connect(
 		"app-get-config",
 		func(application *gtk.Application, section string, option string) string {
-			result := ""
-			if exists, _ := app.config.HasOption(section, option); exists {
-				result, _ = app.config.Get(section, option)
-				result = strings.ReplaceAll(result, " ", "")
-			}
-			return result
+			return app.getConfig(section, option)
 		},
 	)
 
@@ -152,6 +142,20 @@ func (app *mainApplication) startup() {
 
 	// Signal to delete a window from the order
 	_, _ = glibown.SignalNewV("app-delete-window-order", glib.TYPE_NONE, 1, glib.TYPE_STRING)
+
+	// Signal to start/stop the auto-rotation
+	_, _ = glibown.SignalNewV("app-auto-rotate", glib.TYPE_NONE, 1, glib.TYPE_BOOLEAN)
+
+	// Signal to synchronize the auto-rotation's state (active, paused) with the UI and AppIndicator
+	_, _ = glibown.SignalNewV("app-auto-rotate-sync-state", glib.TYPE_NONE, 2, glib.TYPE_BOOLEAN, glib.TYPE_BOOLEAN)
+	// Handler
+	app.application.Connect(
+		"app-auto-rotate-sync-state",
+		func(application *gtk.Application, active bool, paused bool) {
+			app.gui.UpdateAutoRotateState(active, paused)
+			app.appIndicator.UpdateAutoRotateState(active, paused)
+		},
+	)
 }
 
 // Callback of signal "activate" of the application
@@ -165,12 +169,33 @@ func (app *mainApplication) activate(debug bool) {
 			gui.GetTitle(),
 			getStringResource,
 		)
-		app.gui = gui.NewMainGUI(app.application, showWindow, getResource, getStringResource)
+		app.gui = gui.NewMainGUI(app.application, showWindow, getResource, getStringResource, xConn)
 	} else {
 		app.gui.PresentWindow()
 	}
 }
 
+// Load config file, if it doesn't exist or can't be read the config is empty
+func (app *mainApplication) loadConfig() {
+	app.config = configparser.New()
+	if result, _ := configFile.IsFile(); result {
+		config, err := configparser.NewConfigParserFromFile(configFile.String())
+		if err == nil {
+			app.config = config
+		}
+	}
+}
+
+// Get the value of an option from config file without spaces, empty if the option doesn't exist
+func (app *mainApplication) getConfig(section string, option string) string {
+	result := ""
+	if exists, _ := app.config.HasOption(section, option); exists {
+		result, _ = app.config.Get(section, option)
+		result = strings.ReplaceAll(result, " ", "")
+	}
+	return result
+}
+
 // Update config file
 func (app *mainApplication) updateConfig(section string, option string, value string) error {
 	_ = app.config.AddSection(section)
@@ -236,6 +261,7 @@ var (
 	iconFileDisabled *pathlib.Path
 	showWindow       = true
 	localizer        *i18n.Localizer
+	xConn            *xlib.Conn // Connection to the X server
 )
 
 // Function that sets-up the locale configuration
@@ -305,6 +331,11 @@ func main() {
 		"Start the application only in the tray area (appindicator), not showing the main window.",
 	)
 	debugFlag := flag.Bool("debug", false, "Display debug information, keyboard events.")
+	explainFlag := flag.Bool(
+		"explain",
+		false,
+		"Print why every open window is or isn't in the rotation (rule that matched or built-in skip) and exit.",
+	)
 
 	// Parse the flags
 	flag.Parse()
@@ -320,9 +351,23 @@ func main() {
 		log.Fatal("An error occurred creating the application. ", err)
 	}
 
-	xlib.OpenDisplay()     // Open connection to X server
+	// Open connection to X server
+	xConn, err = xlib.OpenConn()
+	if err != nil {
+		log.Fatal("An error occurred connecting to the X server. ", err)
+	}
 	glib.SetPrgname(appId) // Setting the property "WM_CLASS"
 	configFile = pathlib.NewPath(getPathExecutbale(false)).Parent().Join(configFileName)
+
+	// Only the windows are explained, the application doesn't start
+	if *explainFlag {
+		mainApplication := newApplication(application)
+		mainApplication.loadConfig()
+		gui.ExplainWindows(xConn, appId, mainApplication.getConfig, getStringResource)
+		xConn.Close()
+		return
+	}
+
 	iconFile = pathlib.NewPath(getPathExecutbale(true)).Parent().Join(resourcesFolderName, iconFileName)
 	iconFileDisabled = pathlib.NewPath(getPathExecutbale(true)).Parent().Join(resourcesFolderName, iconDisabledFileName)
 